@@ -0,0 +1,347 @@
+package jmdict
+
+import (
+	"strings"
+	"unicode"
+)
+
+// alignedPart is a segment of an expression aligned with its reading. Kana
+// segments are their own reading and have no source; each kanji segment
+// covers a single character.
+type alignedPart struct {
+	text    string
+	reading string
+	kana    bool
+
+	// The KANJIDIC2 reading the segment was matched against, and its
+	// r_type, when the segment is a single kanji.
+	source string
+	kind   string
+}
+
+type kanjiReadingCandidate struct {
+	reading string
+	source  string
+	kind    string
+}
+
+// aligner splits expressions into segments based on the readings of the
+// characters in KANJIDIC2.
+type aligner struct {
+	kanji *KanjiIndex
+}
+
+// maxAlignments bounds the number of alignments explored for a word. Most
+// of them differ only in which reading of a kanji produced the same kana.
+const maxAlignments = 32
+
+// align returns the alignment of the expression with its reading in which
+// every kanji is matched against one of its KANJIDIC2 readings. The result
+// is false if there is no such alignment, or more than one.
+func (a *aligner) align(expression, reading string) ([]alignedPart, bool) {
+	expr := []rune(expression)
+	read := []rune(reading)
+	norm := []rune(katakanaToHiragana(reading))
+
+	var results [][]alignedPart
+	a.search(expr, read, norm, 0, 0, nil, &results)
+
+	if len(results) == 0 {
+		return nil, false
+	}
+
+	best := results[0]
+	for _, result := range results[1:] {
+		if !sameBoundaries(result, best) {
+			return nil, false
+		}
+		if okuriganaMatches(result) > okuriganaMatches(best) {
+			best = result
+		}
+	}
+
+	return best, true
+}
+
+// okuriganaMatches counts the kanji matched against a kun reading whose
+// okurigana follows them in the expression, which is how alignments
+// differing only in the source of the readings are told apart (大きい is
+// おお.きい rather than the prefix おお-).
+func okuriganaMatches(parts []alignedPart) int {
+	var count int
+	for i, part := range parts {
+		if part.kind != "ja_kun" || i+1 == len(parts) {
+			continue
+		}
+
+		var rest strings.Builder
+		for _, next := range parts[i+1:] {
+			rest.WriteString(next.text)
+		}
+
+		kun := ParseKunReading(part.source)
+		if kun.Okurigana != "" && strings.HasPrefix(katakanaToHiragana(rest.String()), kun.Okurigana) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (a *aligner) search(expr, read, norm []rune, i, j int, parts []alignedPart, results *[][]alignedPart) {
+	if len(*results) >= maxAlignments {
+		return
+	}
+
+	if i == len(expr) {
+		if j == len(norm) {
+			*results = append(*results, append([]alignedPart(nil), parts...))
+		}
+		return
+	}
+
+	c := expr[i]
+	if isKana(c) && !isKanaCounter(c) {
+		if j < len(norm) && []rune(katakanaToHiragana(string(c)))[0] == norm[j] {
+			a.search(expr, read, norm, i+1, j+1, appendKana(parts, c, read[j]), results)
+		}
+		return
+	}
+
+	for _, candidate := range a.candidates(expr, i) {
+		r := []rune(candidate.reading)
+		if !hasRunePrefix(norm[j:], r) {
+			continue
+		}
+
+		part := alignedPart{
+			text:    string(c),
+			reading: string(read[j : j+len(r)]),
+			source:  candidate.source,
+			kind:    candidate.kind,
+		}
+		a.search(expr, read, norm, i+1, j+len(r), append(parts, part), results)
+	}
+}
+
+// candidates returns the possible readings, in hiragana, of the character
+// at position i of the expression, including the sound changes it may
+// undergo in compounds.
+func (a *aligner) candidates(expr []rune, i int) []kanjiReadingCandidate {
+	c := expr[i]
+
+	var base []kanjiReadingCandidate
+	switch {
+	case isKanaCounter(c):
+		for _, r := range []string{"か", "が", "こ"} {
+			base = append(base, kanjiReadingCandidate{reading: r, source: r})
+		}
+	case c == '々' && i > 0:
+		base = a.baseCandidates(expr[i-1])
+	default:
+		base = a.baseCandidates(c)
+	}
+
+	seen := make(map[kanjiReadingCandidate]bool)
+	var candidates []kanjiReadingCandidate
+	add := func(candidate kanjiReadingCandidate, reading string) {
+		candidate.reading = reading
+		if reading != "" && !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	for _, candidate := range base {
+		add(candidate, candidate.reading)
+	}
+
+	for _, candidate := range base {
+		if i > 0 {
+			add(candidate, rendaku(candidate.reading))
+			add(candidate, semiVoiced(candidate.reading))
+		}
+		if i < len(expr)-1 {
+			add(candidate, gemination(candidate.reading))
+			if i > 0 {
+				add(candidate, gemination(rendaku(candidate.reading)))
+			}
+		}
+	}
+
+	return candidates
+}
+
+func (a *aligner) baseCandidates(c rune) []kanjiReadingCandidate {
+	character, ok := a.kanji.Lookup(string(c))
+	if !ok || character.ReadingMeaning == nil {
+		return nil
+	}
+
+	var candidates []kanjiReadingCandidate
+	add := func(reading string, source, kind string) {
+		if reading != "" {
+			candidates = append(candidates, kanjiReadingCandidate{reading: reading, source: source, kind: kind})
+		}
+	}
+
+	rm := character.ReadingMeaning
+	for _, group := range rm.Groups {
+		for _, reading := range group.Readings {
+			switch reading.Type {
+			case "ja_on":
+				add(katakanaToHiragana(ParseKunReading(reading.Value).Reading()), reading.Value, reading.Type)
+			case "ja_kun":
+				kun := ParseKunReading(reading.Value)
+				add(kun.Reading(), reading.Value, reading.Type)
+				if kun.Okurigana != "" {
+					// In compounds the okurigana is usually dropped, leaving
+					// the stem or the continuative form (取り扱い, 取扱).
+					add(kun.Stem, reading.Value, reading.Type)
+					add(continuativeForm(kun), reading.Value, reading.Type)
+				}
+			}
+		}
+	}
+
+	for _, nanori := range rm.Nanori {
+		add(katakanaToHiragana(nanori), nanori, "nanori")
+	}
+
+	return candidates
+}
+
+// continuativeForm returns the reading of the continuative (masu stem)
+// form of a verb, or the empty string if the reading is not a verb.
+func continuativeForm(kun KunReading) string {
+	okurigana := []rune(kun.Okurigana)
+	last := okurigana[len(okurigana)-1]
+
+	if len(okurigana) > 1 && last == 'る' {
+		if isIOrERow(okurigana[len(okurigana)-2]) {
+			return kun.Stem + string(okurigana[:len(okurigana)-1])
+		}
+	}
+
+	if shifted, ok := godanContinuative[last]; ok {
+		return kun.Stem + string(okurigana[:len(okurigana)-1]) + string(shifted)
+	}
+
+	return ""
+}
+
+var godanContinuative = map[rune]rune{
+	'う': 'い', 'く': 'き', 'ぐ': 'ぎ', 'す': 'し', 'つ': 'ち',
+	'ぬ': 'に', 'ぶ': 'び', 'む': 'み', 'る': 'り',
+}
+
+// isIOrERow reports whether the kana ends in i or e, which together with
+// a final る marks an ichidan verb (食べる, 見る).
+func isIOrERow(c rune) bool {
+	return strings.ContainsRune("いきしちにひみりぎじぢびぴえけせてねへめれげぜでべぺ", c)
+}
+
+var rendakuVoicing = map[rune]rune{
+	'か': 'が', 'き': 'ぎ', 'く': 'ぐ', 'け': 'げ', 'こ': 'ご',
+	'さ': 'ざ', 'し': 'じ', 'す': 'ず', 'せ': 'ぜ', 'そ': 'ぞ',
+	'た': 'だ', 'ち': 'ぢ', 'つ': 'づ', 'て': 'で', 'と': 'ど',
+	'は': 'ば', 'ひ': 'び', 'ふ': 'ぶ', 'へ': 'べ', 'ほ': 'ぼ',
+}
+
+var semiVoicing = map[rune]rune{
+	'は': 'ぱ', 'ひ': 'ぴ', 'ふ': 'ぷ', 'へ': 'ぺ', 'ほ': 'ぽ',
+}
+
+// rendaku returns the reading with its first kana voiced (か→が), or the
+// empty string if it cannot be.
+func rendaku(reading string) string {
+	return replaceFirstKana(reading, rendakuVoicing)
+}
+
+// semiVoiced returns the reading with its first kana changed from the h
+// to the p row, as after a geminated kanji (杯 はい in 一杯 いっぱい), or
+// the empty string if it cannot be.
+func semiVoiced(reading string) string {
+	return replaceFirstKana(reading, semiVoicing)
+}
+
+func replaceFirstKana(reading string, replacements map[rune]rune) string {
+	r := []rune(reading)
+	if len(r) == 0 {
+		return ""
+	}
+
+	replacement, ok := replacements[r[0]]
+	if !ok {
+		return ""
+	}
+
+	r[0] = replacement
+	return string(r)
+}
+
+// gemination returns the reading with its final つ, ち, く or き replaced
+// by the small っ (学 がく in 学校 がっこう), or the empty string.
+func gemination(reading string) string {
+	r := []rune(reading)
+	if len(r) < 2 {
+		return ""
+	}
+
+	switch r[len(r)-1] {
+	case 'つ', 'ち', 'く', 'き':
+		r[len(r)-1] = 'っ'
+		return string(r)
+	}
+
+	return ""
+}
+
+// isKanaCounter reports whether the character is the small ke used as a
+// counter (一ヶ月), which is read as か, が or こ rather than as itself.
+func isKanaCounter(c rune) bool {
+	return c == 'ヶ' || c == 'ケ' || c == 'ヵ'
+}
+
+func isKanji(c rune) bool {
+	return unicode.Is(unicode.Han, c)
+}
+
+func appendKana(parts []alignedPart, c, reading rune) []alignedPart {
+	if n := len(parts); n > 0 && parts[n-1].kana {
+		last := parts[n-1]
+		last.text += string(c)
+		last.reading += string(reading)
+		return append(parts[:n-1:n-1], last)
+	}
+
+	return append(parts, alignedPart{text: string(c), reading: string(reading), kana: true})
+}
+
+func hasRunePrefix(text, prefix []rune) bool {
+	if len(prefix) > len(text) {
+		return false
+	}
+
+	for i, c := range prefix {
+		if text[i] != c {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameBoundaries(a, b []alignedPart) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].text != b[i].text || a[i].reading != b[i].reading {
+			return false
+		}
+	}
+
+	return true
+}
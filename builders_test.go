@@ -0,0 +1,134 @@
+package jmdict
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// testEntry builds a JMdict entry with a kanji element for each of the
+// comma-separated kanji, a reading element for each of the comma-separated
+// readings, and the senses.
+func testEntry(sequence int, kanji, readings string, senses ...JmdictSense) JmdictEntry {
+	entry := JmdictEntry{Sequence: sequence, Sense: senses}
+	for _, expression := range splitTestList(kanji) {
+		entry.Kanji = append(entry.Kanji, JmdictKanji{Expression: expression})
+	}
+	for _, reading := range splitTestList(readings) {
+		entry.Readings = append(entry.Readings, JmdictReading{Reading: reading})
+	}
+
+	return entry
+}
+
+// testSense builds a sense of the comma-separated parts of speech, with a
+// gloss for each of the glosses.
+func testSense(partsOfSpeech string, glosses ...string) JmdictSense {
+	sense := JmdictSense{PartsOfSpeech: splitTestList(partsOfSpeech)}
+	for _, gloss := range glosses {
+		sense.Glossary = append(sense.Glossary, JmdictGlossary{Content: gloss})
+	}
+
+	return sense
+}
+
+// testName builds a JMnedict entry with the kanji element, if any, the
+// reading element and a translation of the name types.
+func testName(sequence int, kanji, reading string, types ...string) JmnedictEntry {
+	entry := JmnedictEntry{
+		Sequence:     sequence,
+		Readings:     []JmnedictReading{{Reading: reading}},
+		Translations: []JmnedictTranslation{{NameTypes: types}},
+	}
+	if kanji != "" {
+		entry.Kanji = []JmnedictKanji{{Expression: kanji}}
+	}
+
+	return entry
+}
+
+// testCharacter builds a KANJIDIC2 character with a single group of the
+// comma-separated readings, which are on readings if written in katakana
+// and kun readings otherwise, and meanings.
+func testCharacter(literal, readings, meanings string) KanjidicCharacter {
+	var group KanjidicRMGroup
+	for _, reading := range splitTestList(readings) {
+		kind := "ja_kun"
+		if katakanaToHiragana(reading) != reading {
+			kind = "ja_on"
+		}
+		group.Readings = append(group.Readings, KanjidicReading{Value: reading, Type: kind})
+	}
+	for _, meaning := range splitTestList(meanings) {
+		group.Meanings = append(group.Meanings, KanjidicMeaning{Meaning: meaning})
+	}
+
+	return KanjidicCharacter{
+		Literal:        literal,
+		ReadingMeaning: &KanjidicReadingMeaning{Groups: []KanjidicRMGroup{group}},
+	}
+}
+
+func splitTestList(values string) []string {
+	if values == "" {
+		return nil
+	}
+
+	return strings.Split(values, ",")
+}
+
+// testEntryXML returns the XML form of the entry, which compares entries
+// regardless of how empty lists are represented.
+func testEntryXML(t *testing.T, entry *JmdictEntry) string {
+	t.Helper()
+
+	data, err := xml.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// testJmdictXML is a small JMdict file with an internal DTD subset.
+const testJmdictXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ELEMENT JMdict (entry*)>
+<!ELEMENT entry (ent_seq, k_ele*, r_ele+, sense+)>
+<!ATTLIST gloss xml:lang CDATA "eng" g_type CDATA #IMPLIED>
+<!ENTITY v1 "Ichidan verb">
+<!ENTITY n "noun (common) (futsuumeishi)">
+]>
+<!-- JMdict created: 2022-07-14 -->
+<JMdict>
+<entry>
+<ent_seq>1358280</ent_seq>
+<k_ele>
+<keb>食べる</keb>
+<ke_pri>ichi1</ke_pri>
+</k_ele>
+<r_ele>
+<reb>たべる</reb>
+<re_pri>ichi1</re_pri>
+</r_ele>
+<sense>
+<pos>&v1;</pos>
+<gloss>to eat</gloss>
+<gloss xml:lang="ger">essen</gloss>
+</sense>
+</entry>
+<entry>
+<ent_seq>1206730</ent_seq>
+<k_ele>
+<keb>学校</keb>
+</k_ele>
+<r_ele>
+<reb>がっこう</reb>
+</r_ele>
+<sense>
+<pos>&n;</pos>
+<gloss>school</gloss>
+</sense>
+</entry>
+</JMdict>
+`
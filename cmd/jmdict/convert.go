@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"foosoft.net/projects/jmdict"
+)
+
+// filterFlags holds the flags selecting the subset of a dictionary to load.
+type filterFlags struct {
+	languages  string
+	common     bool
+	priorities string
+	pos        string
+	fields     string
+	misc       string
+	dialects   string
+	nameTypes  string
+	grades     string
+	jlpt       string
+}
+
+func (ff *filterFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&ff.languages, "lang", "", "comma-separated ISO 639-2 codes of the languages to keep (e.g. eng,ger)")
+	flags.BoolVar(&ff.common, "common", false, "only keep common JMdict entries")
+	flags.StringVar(&ff.priorities, "priority", "", "only keep JMdict entries with any of the comma-separated priority codes")
+	flags.StringVar(&ff.pos, "pos", "", "only keep JMdict entries with any of the comma-separated part-of-speech tags")
+	flags.StringVar(&ff.fields, "field", "", "only keep JMdict entries with any of the comma-separated field tags")
+	flags.StringVar(&ff.misc, "misc", "", "only keep JMdict entries with any of the comma-separated misc tags")
+	flags.StringVar(&ff.dialects, "dial", "", "only keep JMdict entries with any of the comma-separated dialect tags")
+	flags.StringVar(&ff.nameTypes, "name-type", "", "only keep JMnedict entries with any of the comma-separated name types")
+	flags.StringVar(&ff.grades, "grade", "", "only keep kanji taught in any of the comma-separated grades")
+	flags.StringVar(&ff.jlpt, "jlpt", "", "only keep kanji at any of the comma-separated JLPT levels")
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func splitIntList(name, value string) ([]int, error) {
+	var values []int
+	for _, v := range splitList(value) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -%s value %q", name, v)
+		}
+		values = append(values, n)
+	}
+
+	return values, nil
+}
+
+// options converts the filter flags to load options.
+func (ff *filterFlags) options() ([]jmdict.LoadOption, error) {
+	var options []jmdict.LoadOption
+	if languages := splitList(ff.languages); len(languages) > 0 {
+		options = append(options, jmdict.WithLanguages(languages...))
+	}
+
+	var jmdictFilters []jmdict.Predicate[jmdict.JmdictEntry]
+	if ff.common {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictCommon())
+	}
+	if values := splitList(ff.priorities); len(values) > 0 {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictPriority(values...))
+	}
+	if values := splitList(ff.pos); len(values) > 0 {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictPartOfSpeech(values...))
+	}
+	if values := splitList(ff.fields); len(values) > 0 {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictField(values...))
+	}
+	if values := splitList(ff.misc); len(values) > 0 {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictMisc(values...))
+	}
+	if values := splitList(ff.dialects); len(values) > 0 {
+		jmdictFilters = append(jmdictFilters, jmdict.JmdictDialect(values...))
+	}
+	if len(jmdictFilters) > 0 {
+		options = append(options, jmdict.WithJmdictFilter(jmdict.And(jmdictFilters...)))
+	}
+
+	if values := splitList(ff.nameTypes); len(values) > 0 {
+		options = append(options, jmdict.WithJmnedictFilter(jmdict.JmnedictNameType(values...)))
+	}
+
+	var kanjidicFilters []jmdict.Predicate[jmdict.KanjidicCharacter]
+	grades, err := splitIntList("grade", ff.grades)
+	if err != nil {
+		return nil, err
+	}
+	if len(grades) > 0 {
+		kanjidicFilters = append(kanjidicFilters, jmdict.KanjidicGrade(grades...))
+	}
+
+	levels, err := splitIntList("jlpt", ff.jlpt)
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) > 0 {
+		kanjidicFilters = append(kanjidicFilters, jmdict.KanjidicJlpt(levels...))
+	}
+	if len(kanjidicFilters) > 0 {
+		options = append(options, jmdict.WithKanjidicFilter(jmdict.And(kanjidicFilters...)))
+	}
+
+	return options, nil
+}
+
+func runConvert(args []string) error {
+	cmd := findCommand("convert")
+
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jmdict %s\n\n", cmd.usage)
+		fmt.Fprintf(os.Stderr, "The input is an XML file or, for JMdict, an EDICT2 file, optionally gzipped,\n")
+		fmt.Fprintf(os.Stderr, "or a snapshot written by this command; \"-\" reads from standard input.\n")
+		fmt.Fprintf(os.Stderr, "Output formats:\n")
+		fmt.Fprintf(os.Stderr, "  xml, json, jsonl, sql, csv, snapshot, yomichan (a zip archive to import\n")
+		fmt.Fprintf(os.Stderr, "  into Yomichan), and edict2 (JMdict only).\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	var (
+		kind      string
+		format    string
+		output    string
+		title     string
+		transform bool
+		ff        filterFlags
+	)
+
+	flags.StringVar(&kind, "type", "jmdict", "type of the input dictionary: jmdict, jmnedict or kanjidic")
+	flags.StringVar(&format, "format", "json", "output format")
+	flags.StringVar(&output, "o", "-", "output file, or directory for csv; gzipped if it ends in .gz")
+	flags.StringVar(&title, "title", "", "title of yomichan dictionaries (default JMdict, JMnedict or KANJIDIC2)")
+	flags.BoolVar(&transform, "expand", false, "expand entity codes (e.g. v1) to their descriptions")
+	ff.register(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	options, err := ff.options()
+	if err != nil {
+		return err
+	}
+
+	input := flags.Arg(0)
+	switch kind {
+	case "jmdict":
+		dict, err := loadJmdict(input, transform, options...)
+		if err != nil {
+			return err
+		}
+		return writeOutput(output, format, dict, dict.Entries, jmdictConverter(dict, defaultTitle(title, "JMdict")))
+	case "jmnedict":
+		dic, err := loadJmnedict(input, transform, options...)
+		if err != nil {
+			return err
+		}
+		return writeOutput(output, format, dic, dic.Entries, jmnedictConverter(dic, defaultTitle(title, "JMnedict")))
+	case "kanjidic":
+		dic, err := loadKanjidic(input, options...)
+		if err != nil {
+			return err
+		}
+		return writeOutput(output, format, dic, dic.Characters, kanjidicConverter(dic, defaultTitle(title, "KANJIDIC2")))
+	default:
+		return fmt.Errorf("unknown dictionary type %q", kind)
+	}
+}
+
+func defaultTitle(title, name string) string {
+	if title == "" {
+		return name
+	}
+
+	return title
+}
+
+// converter holds the format writers which depend on the dictionary type.
+// Writers are nil for formats the dictionary type does not support.
+type converter struct {
+	xml      func(io.Writer) error
+	snapshot func(io.Writer) error
+	edict2   func(io.Writer) error
+	yomichan func(io.Writer) error
+	tables   func() ([]jmdict.SQLTable, error)
+}
+
+func jmdictConverter(dict jmdict.Jmdict, title string) converter {
+	return converter{
+		xml:      func(w io.Writer) error { return jmdict.WriteJmdictXML(w, dict) },
+		snapshot: func(w io.Writer) error { return jmdict.WriteJmdictSnapshot(w, dict) },
+		edict2:   func(w io.Writer) error { return jmdict.WriteEdict2(w, dict) },
+		yomichan: func(w io.Writer) error { return jmdict.WriteJmdictYomichan(w, dict, title) },
+		tables:   func() ([]jmdict.SQLTable, error) { return jmdict.JmdictTables(dict), nil },
+	}
+}
+
+func jmnedictConverter(dic jmdict.Jmnedict, title string) converter {
+	return converter{
+		xml:      func(w io.Writer) error { return jmdict.WriteJmnedictXML(w, dic) },
+		snapshot: func(w io.Writer) error { return jmdict.WriteJmnedictSnapshot(w, dic) },
+		yomichan: func(w io.Writer) error { return jmdict.WriteJmnedictYomichan(w, dic, title) },
+		tables:   func() ([]jmdict.SQLTable, error) { return jmdict.JmnedictTables(dic), nil },
+	}
+}
+
+func kanjidicConverter(dic jmdict.Kanjidic, title string) converter {
+	return converter{
+		xml:      func(w io.Writer) error { return jmdict.WriteKanjidicXML(w, dic) },
+		snapshot: func(w io.Writer) error { return jmdict.WriteKanjidicSnapshot(w, dic) },
+		yomichan: func(w io.Writer) error { return jmdict.WriteKanjidicYomichan(w, dic, title) },
+		tables:   func() ([]jmdict.SQLTable, error) { return jmdict.KanjidicTables(dic) },
+	}
+}
+
+func writeOutput[E any](path, format string, dict interface{}, entries []E, conv converter) error {
+	if format == "csv" {
+		return writeCSVDirectory(path, conv)
+	}
+
+	var write func(io.Writer) error
+	switch format {
+	case "xml":
+		write = conv.xml
+	case "snapshot":
+		write = conv.snapshot
+	case "edict2":
+		write = conv.edict2
+	case "yomichan":
+		write = conv.yomichan
+	case "json":
+		write = func(w io.Writer) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetEscapeHTML(false)
+			return encoder.Encode(dict)
+		}
+	case "jsonl":
+		write = func(w io.Writer) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetEscapeHTML(false)
+			for i := range entries {
+				if err := encoder.Encode(&entries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case "sql":
+		write = func(w io.Writer) error {
+			tables, err := conv.tables()
+			if err != nil {
+				return err
+			}
+			if err := jmdict.WriteSQLSchema(w, tables); err != nil {
+				return err
+			}
+			return jmdict.WriteSQLInserts(w, tables)
+		}
+	}
+
+	if write == nil {
+		return fmt.Errorf("unsupported output format %q for this dictionary", format)
+	}
+
+	return createOutput(path, write)
+}
+
+// createOutput writes to the file at path, or standard output for "-",
+// compressing the output when the path ends in ".gz".
+func createOutput(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		w := bufio.NewWriter(os.Stdout)
+		if err := write(w); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	var out io.Writer = w
+
+	var gz *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(w)
+		out = gz
+	}
+
+	err = write(out)
+	if gz != nil && err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// writeCSVDirectory writes the relational schema to schema.sql and each
+// table to a CSV file of the same name in the directory.
+func writeCSVDirectory(dir string, conv converter) error {
+	if dir == "-" {
+		return errors.New("csv output requires an output directory (-o)")
+	}
+
+	tables, err := conv.tables()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	err = createOutput(filepath.Join(dir, "schema.sql"), func(w io.Writer) error {
+		return jmdict.WriteSQLSchema(w, tables)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		table := table
+		err := createOutput(filepath.Join(dir, table.Name+".csv"), func(w io.Writer) error {
+			return jmdict.WriteCSV(w, table)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"foosoft.net/projects/jmdict"
+)
+
+var commonPriorities = map[string]bool{
+	"news1": true,
+	"ichi1": true,
+	"spec1": true,
+	"spec2": true,
+	"gai1":  true,
+}
+
+func isCommon(priorities []string) bool {
+	for _, priority := range priorities {
+		if commonPriorities[priority] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatTags(tags ...[]string) string {
+	var all []string
+	for _, t := range tags {
+		all = append(all, t...)
+	}
+
+	if len(all) == 0 {
+		return ""
+	}
+
+	return "(" + strings.Join(all, ", ") + ") "
+}
+
+// writeJmdictEntry prints an entry as its headwords, each marked with (P)
+// when common, followed by its numbered senses.
+func writeJmdictEntry(w io.Writer, entry *jmdict.JmdictEntry) {
+	var kanji []string
+	for _, k := range entry.Kanji {
+		text := k.Expression
+		if isCommon(k.Priorities) {
+			text += "(P)"
+		}
+		kanji = append(kanji, text)
+	}
+
+	var readings []string
+	for _, r := range entry.Readings {
+		text := r.Reading
+		if len(r.Restrictions) > 0 {
+			text += "{" + strings.Join(r.Restrictions, ";") + "}"
+		}
+		if isCommon(r.Priorities) {
+			text += "(P)"
+		}
+		readings = append(readings, text)
+	}
+
+	if len(kanji) > 0 {
+		fmt.Fprintf(w, "%s【%s】", strings.Join(kanji, "; "), strings.Join(readings, "; "))
+	} else {
+		fmt.Fprint(w, strings.Join(readings, "; "))
+	}
+	fmt.Fprintf(w, " #%d\n", entry.Sequence)
+
+	for i, sense := range entry.Sense {
+		var glosses []string
+		for _, gloss := range sense.Glossary {
+			text := gloss.Content
+			if gloss.Language != nil && *gloss.Language != "eng" {
+				text = fmt.Sprintf("[%s] %s", *gloss.Language, text)
+			}
+			glosses = append(glosses, text)
+		}
+
+		fmt.Fprintf(w, "  %d. %s%s\n", i+1, formatTags(sense.PartsOfSpeech, sense.Fields, sense.Misc, sense.Dialects), strings.Join(glosses, "; "))
+
+		for _, info := range sense.Information {
+			fmt.Fprintf(w, "     note: %s\n", info)
+		}
+		if len(sense.References) > 0 {
+			fmt.Fprintf(w, "     see: %s\n", strings.Join(sense.References, ", "))
+		}
+		if len(sense.Antonyms) > 0 {
+			fmt.Fprintf(w, "     antonym: %s\n", strings.Join(sense.Antonyms, ", "))
+		}
+	}
+
+	fmt.Fprintln(w)
+}
+
+func writeJmnedictEntry(w io.Writer, entry *jmdict.JmnedictEntry) {
+	var kanji []string
+	for _, k := range entry.Kanji {
+		kanji = append(kanji, k.Expression)
+	}
+
+	var readings []string
+	for _, r := range entry.Readings {
+		readings = append(readings, r.Reading)
+	}
+
+	if len(kanji) > 0 {
+		fmt.Fprintf(w, "%s【%s】", strings.Join(kanji, "; "), strings.Join(readings, "; "))
+	} else {
+		fmt.Fprint(w, strings.Join(readings, "; "))
+	}
+	fmt.Fprintf(w, " #%d\n", entry.Sequence)
+
+	for _, trans := range entry.Translations {
+		fmt.Fprintf(w, "  %s%s\n", formatTags(trans.NameTypes), strings.Join(trans.Translations, "; "))
+	}
+
+	fmt.Fprintln(w)
+}
+
+func writeKanjidicCharacter(w io.Writer, character *jmdict.KanjidicCharacter) {
+	misc := character.Misc
+
+	var details []string
+	if len(misc.StrokeCounts) > 0 {
+		details = append(details, misc.StrokeCounts[0]+" strokes")
+	}
+	if misc.Grade != nil {
+		details = append(details, "grade "+*misc.Grade)
+	}
+	if misc.JlptLevel != nil {
+		details = append(details, "JLPT "+*misc.JlptLevel)
+	}
+	if misc.Frequency != nil {
+		details = append(details, "frequency "+*misc.Frequency)
+	}
+
+	fmt.Fprintf(w, "%s  %s\n", character.Literal, strings.Join(details, ", "))
+
+	if rm := character.ReadingMeaning; rm != nil {
+		var on, kun, meanings []string
+		for _, group := range rm.Groups {
+			for _, reading := range group.Readings {
+				switch reading.Type {
+				case "ja_on":
+					on = append(on, reading.Value)
+				case "ja_kun":
+					kun = append(kun, reading.Value)
+				}
+			}
+
+			for _, meaning := range group.Meanings {
+				if meaning.Language == nil || *meaning.Language == "en" {
+					meanings = append(meanings, meaning.Meaning)
+				}
+			}
+		}
+
+		writeField(w, "on", on)
+		writeField(w, "kun", kun)
+		writeField(w, "nanori", rm.Nanori)
+		writeField(w, "meaning", meanings)
+	}
+
+	fmt.Fprintln(w)
+}
+
+func writeField(w io.Writer, name string, values []string) {
+	if len(values) > 0 {
+		fmt.Fprintf(w, "  %-8s %s\n", name+":", strings.Join(values, ", "))
+	}
+}
+
+// writeKanjiWord prints a word written with a kanji on a single line, with
+// the reading of the kanji in the word and the first sense of the entry.
+func writeKanjiWord(w io.Writer, word *jmdict.KanjiWord) {
+	fmt.Fprintf(w, "%s【%s】", word.Expression, word.Reading)
+	if word.KanjiReading != "" {
+		fmt.Fprintf(w, " [%s]", word.KanjiReading)
+	}
+
+	if len(word.Entry.Sense) > 0 {
+		var glosses []string
+		for _, gloss := range word.Entry.Sense[0].Glossary {
+			glosses = append(glosses, gloss.Content)
+		}
+		fmt.Fprintf(w, " %s", strings.Join(glosses, "; "))
+	}
+
+	fmt.Fprintf(w, " #%d\n", word.Entry.Sequence)
+}
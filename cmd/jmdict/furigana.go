@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"foosoft.net/projects/jmdict"
+)
+
+func runFurigana(args []string) error {
+	flags, df := newFlagSet("furigana")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 && flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dic, err := loadKanjidic(df.kanjidic)
+	if err != nil {
+		return err
+	}
+
+	kanji := jmdict.NewKanjiIndex(&dic)
+
+	if flags.NArg() == 2 {
+		segments := jmdict.Furigana(kanji, flags.Arg(0), flags.Arg(1))
+		if df.json {
+			return printJSON(segments)
+		}
+
+		fmt.Println(jmdict.FormatFurigana(segments))
+		return nil
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	furigana := jmdict.AlignJmdictFurigana(&dict, kanji)
+	if df.json {
+		return printJSON(furigana)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, f := range furigana {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Expression, f.Reading, jmdict.FormatFurigana(f.Segments))
+	}
+
+	return w.Flush()
+}
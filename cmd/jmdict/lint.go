@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"foosoft.net/projects/jmdict"
+)
+
+func runLint(args []string) error {
+	flags, df := newFlagSet("lint")
+
+	var kind string
+	flags.StringVar(&kind, "type", "jmdict", "type of the dictionary to check: jmdict or jmnedict")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var problems []jmdict.ValidationProblem
+	switch kind {
+	case "jmdict":
+		dict, err := loadJmdict(df.jmdict, false)
+		if err != nil {
+			return err
+		}
+		problems = jmdict.ValidateJmdict(dict)
+	case "jmnedict":
+		dic, err := loadJmnedict(df.jmnedict, false)
+		if err != nil {
+			return err
+		}
+		problems = jmdict.ValidateJmnedict(dic)
+	default:
+		return fmt.Errorf("unknown dictionary type %q", kind)
+	}
+
+	if df.json {
+		if err := printJSON(problems); err != nil {
+			return err
+		}
+	} else {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+
+	return nil
+}
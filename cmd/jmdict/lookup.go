@@ -0,0 +1,435 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"foosoft.net/projects/jmdict"
+)
+
+var errNoResults = errors.New("no results")
+
+func runLookup(args []string) error {
+	flags, df := newFlagSet("lookup")
+
+	var fold bool
+	flags.BoolVar(&fold, "fold", false, "also look up the term with old and variant kanji forms replaced by modern ones (requires KANJIDIC2)")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	index := jmdict.NewJmdictIndex(&dict)
+	term := strings.Join(flags.Args(), " ")
+	if !fold {
+		return printJmdictEntries(index.LookupTerm(term), df.json)
+	}
+
+	dic, err := loadKanjidic(df.kanjidic)
+	if err != nil {
+		return err
+	}
+
+	entries := index.LookupTermNormalized(term, jmdict.NewKanjiIndex(&dic))
+	return printJmdictEntries(entries, df.json)
+}
+
+func runEnglish(args []string) error {
+	flags, df := newFlagSet("english")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	entries := jmdict.NewJmdictIndex(&dict).LookupGloss(strings.Join(flags.Args(), " "))
+	return printJmdictEntries(entries, df.json)
+}
+
+func runName(args []string) error {
+	flags, df := newFlagSet("name")
+
+	var (
+		nameType  string
+		character string
+	)
+
+	flags.StringVar(&nameType, "type", "", "only find names of the type (e.g. surname or place)")
+	flags.StringVar(&character, "contains", "", "find names of the type written with the character")
+	flags.Parse(args)
+	if flags.NArg() == 0 && character == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var t jmdict.NameType
+	if nameType != "" {
+		var ok bool
+		if t, ok = jmdict.ParseNameType(nameType); !ok {
+			return fmt.Errorf("unknown name type %q", nameType)
+		}
+	} else if character != "" {
+		return errors.New("-contains requires -type")
+	}
+
+	dic, err := loadJmnedict(df.jmnedict, false)
+	if err != nil {
+		return err
+	}
+
+	index := jmdict.NewJmnedictIndex(&dic)
+	term := strings.Join(flags.Args(), " ")
+
+	var entries []*jmdict.JmnedictEntry
+	switch {
+	case character != "":
+		entries = index.LookupCharacterOfType(character, t)
+	case nameType != "":
+		for _, entry := range index.LookupTerm(term) {
+			if entry.HasNameType(t) {
+				entries = append(entries, entry)
+			}
+		}
+	default:
+		entries = index.LookupTerm(term)
+	}
+
+	if df.json {
+		return printJSON(entries)
+	}
+
+	if len(entries) == 0 {
+		return errNoResults
+	}
+
+	for _, entry := range entries {
+		writeJmnedictEntry(os.Stdout, entry)
+	}
+
+	return nil
+}
+
+func runSearch(args []string) error {
+	flags, df := newFlagSet("search")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	dic, err := loadJmnedict(df.jmnedict, false)
+	if err != nil {
+		return err
+	}
+
+	term := strings.Join(flags.Args(), " ")
+	results := jmdict.SearchTerm(jmdict.NewJmdictIndex(&dict), jmdict.NewJmnedictIndex(&dic), term)
+	if df.json {
+		return printJSON(results)
+	}
+
+	if len(results) == 0 {
+		return errNoResults
+	}
+
+	for _, result := range results {
+		if result.Word != nil {
+			writeJmdictEntry(os.Stdout, result.Word)
+		} else {
+			writeJmnedictEntry(os.Stdout, result.Name)
+		}
+	}
+
+	return nil
+}
+
+func runSplit(args []string) error {
+	flags, df := newFlagSet("split")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dic, err := loadJmnedict(df.jmnedict, false)
+	if err != nil {
+		return err
+	}
+
+	splits := jmdict.NewJmnedictIndex(&dic).SplitName(flags.Arg(0))
+	if df.json {
+		return printJSON(splits)
+	}
+
+	if len(splits) == 0 {
+		return errNoResults
+	}
+
+	for _, split := range splits {
+		fmt.Printf("%s %s (%.0f%%)\n", split.Surname, split.Given, split.Confidence*100)
+		fmt.Printf("  surname: %s\n", formatNameReadings(split.SurnameReadings))
+		fmt.Printf("  given:   %s\n\n", formatNameReadings(split.GivenReadings))
+	}
+
+	return nil
+}
+
+func formatNameReadings(readings []jmdict.NameReading) string {
+	parts := make([]string, 0, len(readings))
+	for _, reading := range readings {
+		parts = append(parts, fmt.Sprintf("%s (%d)", reading.Reading, reading.Entries))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func runKanji(args []string) error {
+	flags, df := newFlagSet("kanji")
+
+	var (
+		skip       string
+		misclass   bool
+		fourCorner string
+		variants   bool
+	)
+
+	flags.StringVar(&skip, "skip", "", "look up characters by SKIP code (e.g. 2-2-7)")
+	flags.BoolVar(&misclass, "misclass", true, "include characters for which the SKIP code is a common misclassification")
+	flags.StringVar(&fourCorner, "four-corner", "", "look up characters by Four Corner code (e.g. 8073 or 8073.2)")
+	flags.BoolVar(&variants, "variants", false, "include the variants of the characters")
+
+	var sf kanjiSearchFlags
+	sf.register(flags)
+	flags.Parse(args)
+
+	query, search, err := sf.query()
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 && skip == "" && fourCorner == "" && !search {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var (
+		skipCode       jmdict.SkipCode
+		fourCornerCode jmdict.FourCornerCode
+	)
+
+	if skip != "" {
+		if skipCode, err = jmdict.ParseSkipCode(skip); err != nil {
+			return err
+		}
+	}
+
+	if fourCorner != "" {
+		if fourCornerCode, err = jmdict.ParseFourCornerCode(fourCorner); err != nil {
+			return err
+		}
+	}
+
+	dic, err := loadKanjidic(df.kanjidic)
+	if err != nil {
+		return err
+	}
+
+	index := jmdict.NewKanjiIndex(&dic)
+
+	var characters []*jmdict.KanjidicCharacter
+	for _, c := range strings.Join(flags.Args(), "") {
+		if character, ok := index.Lookup(string(c)); ok {
+			characters = append(characters, character)
+		}
+	}
+
+	if skip != "" {
+		characters = append(characters, index.LookupSkip(skipCode, misclass)...)
+	}
+
+	if fourCorner != "" {
+		characters = append(characters, index.LookupFourCorner(fourCornerCode)...)
+	}
+
+	if search {
+		characters = append(characters, index.Search(query)...)
+	}
+
+	if variants {
+		for _, character := range characters {
+			for _, literal := range index.Variants(character.Literal) {
+				if variant, ok := index.Lookup(literal); ok {
+					characters = append(characters, variant)
+				}
+			}
+		}
+	}
+
+	if df.json {
+		return printJSON(characters)
+	}
+
+	if len(characters) == 0 {
+		return errNoResults
+	}
+
+	for _, character := range characters {
+		writeKanjidicCharacter(os.Stdout, character)
+	}
+
+	return nil
+}
+
+// kanjiSearchFlags holds the flags of the kanji command which search by
+// criteria rather than look up a code.
+type kanjiSearchFlags struct {
+	strokes   string
+	miscounts bool
+	radical   int
+	grades    string
+	jlpt      string
+	frequency int
+	reading   string
+	meaning   string
+	reference string
+}
+
+func (sf *kanjiSearchFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&sf.strokes, "strokes", "", "search by stroke count or inclusive range (e.g. 8 or 8-10)")
+	flags.BoolVar(&sf.miscounts, "miscounts", false, "also match common stroke miscounts")
+	flags.IntVar(&sf.radical, "radical", 0, "search by classical radical number")
+	flags.StringVar(&sf.grades, "grade", "", "search by any of the comma-separated grades")
+	flags.StringVar(&sf.jlpt, "jlpt", "", "search by any of the comma-separated JLPT levels")
+	flags.IntVar(&sf.frequency, "frequency", 0, "only find characters ranked at or above this frequency rank")
+	flags.StringVar(&sf.reading, "reading", "", "search by on or kun reading")
+	flags.StringVar(&sf.meaning, "meaning", "", "search by meaning keywords")
+	flags.StringVar(&sf.reference, "ref", "", "search by dictionary reference type and number (e.g. heisig:1472)")
+}
+
+// query converts the flags to a kanji query, also reporting whether any
+// criteria were given.
+func (sf *kanjiSearchFlags) query() (jmdict.KanjiQuery, bool, error) {
+	query := jmdict.KanjiQuery{
+		Miscounts:    sf.miscounts,
+		Radical:      sf.radical,
+		MaxFrequency: sf.frequency,
+		Reading:      sf.reading,
+		Meaning:      sf.meaning,
+	}
+
+	query.Reference, query.ReferenceNumber, _ = strings.Cut(sf.reference, ":")
+
+	if sf.strokes != "" {
+		min, max, isRange := strings.Cut(sf.strokes, "-")
+		if !isRange {
+			max = min
+		}
+
+		var err1, err2 error
+		query.MinStrokes, err1 = strconv.Atoi(min)
+		query.MaxStrokes, err2 = strconv.Atoi(max)
+		if err1 != nil || err2 != nil {
+			return query, false, fmt.Errorf("invalid -strokes value %q", sf.strokes)
+		}
+	}
+
+	var err error
+	if query.Grades, err = splitIntList("grade", sf.grades); err != nil {
+		return query, false, err
+	}
+	if query.JLPT, err = splitIntList("jlpt", sf.jlpt); err != nil {
+		return query, false, err
+	}
+
+	search := sf.strokes != "" || sf.radical > 0 || len(query.Grades) > 0 || len(query.JLPT) > 0 ||
+		sf.frequency > 0 || sf.reading != "" || sf.meaning != "" || sf.reference != ""
+
+	return query, search, nil
+}
+
+func printJmdictEntries(entries []*jmdict.JmdictEntry, asJSON bool) error {
+	if asJSON {
+		return printJSON(entries)
+	}
+
+	if len(entries) == 0 {
+		return errNoResults
+	}
+
+	for _, entry := range entries {
+		writeJmdictEntry(os.Stdout, entry)
+	}
+
+	return nil
+}
+
+func runWords(args []string) error {
+	flags, df := newFlagSet("words")
+
+	var limit int
+	flags.IntVar(&limit, "limit", 50, "maximum number of words to list, or 0 for all")
+	flags.Parse(args)
+
+	literal, ok := singleCharacter(flags.Args())
+	if !ok {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	dic, err := loadKanjidic(df.kanjidic)
+	if err != nil {
+		return err
+	}
+
+	words := jmdict.NewKanjiWordIndex(&dict, jmdict.NewKanjiIndex(&dic)).TaggedWords(literal)
+	if limit > 0 && len(words) > limit {
+		words = words[:limit]
+	}
+
+	if df.json {
+		return printJSON(words)
+	}
+
+	if len(words) == 0 {
+		return errNoResults
+	}
+
+	for i := range words {
+		writeKanjiWord(os.Stdout, &words[i])
+	}
+
+	return nil
+}
+
+func singleCharacter(args []string) (string, bool) {
+	if len(args) != 1 || len([]rune(args[0])) != 1 {
+		return "", false
+	}
+
+	return args[0], true
+}
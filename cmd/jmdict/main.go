@@ -0,0 +1,260 @@
+// Command jmdict looks up words, names and kanji in local copies of the
+// JMdict, JMnedict and KANJIDIC2 files, which may optionally be gzipped,
+// and converts between the formats supported by the library.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"foosoft.net/projects/jmdict"
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"lookup", "lookup [flags] <expression or reading>", "look up words in JMdict", runLookup},
+		{"english", "english [flags] <words>", "look up words in JMdict by English gloss", runEnglish},
+		{"name", "name [flags] <name or reading>", "look up names in JMnedict", runName},
+		{"search", "search [flags] <expression or reading>", "look up words in JMdict and names in JMnedict", runSearch},
+		{"split", "split [flags] <full name>", "split a full name into family and given name using JMnedict", runSplit},
+		{"kanji", "kanji [flags] [characters]", "look up characters in KANJIDIC2", runKanji},
+		{"words", "words [flags] <kanji>", "list the JMdict words written with a kanji", runWords},
+		{"tokenize", "tokenize [flags] <text>", "split text into JMdict words", runTokenize},
+		{"furigana", "furigana [flags] [expression reading]", "align readings with kanji, for a word or all JMdict headwords", runFurigana},
+		{"convert", "convert [flags] <input file>", "convert a dictionary to another format", runConvert},
+		{"lint", "lint [flags]", "check a dictionary for broken references and invalid values", runLint},
+		{"stats", "stats [flags]", "print statistics about a dictionary", runStats},
+		{"serve", "serve [flags]", "serve lookups as JSON over HTTP", runServe},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: jmdict <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"jmdict <command> -h\" for the flags of a command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "jmdict %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func findCommand(name string) command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	panic("unknown command " + name)
+}
+
+// dictFlags holds the flags shared by the commands which load dictionaries.
+type dictFlags struct {
+	jmdict   string
+	jmnedict string
+	kanjidic string
+	json     bool
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *dictFlags) {
+	cmd := findCommand(name)
+
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jmdict %s\n\nFlags:\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+
+	var df dictFlags
+	flags.StringVar(&df.jmdict, "jmdict", envOrDefault("JMDICT_PATH", "JMdict_e.gz"), "path to the JMdict file ($JMDICT_PATH)")
+	flags.StringVar(&df.jmnedict, "jmnedict", envOrDefault("JMNEDICT_PATH", "JMnedict.xml.gz"), "path to the JMnedict file ($JMNEDICT_PATH)")
+	flags.StringVar(&df.kanjidic, "kanjidic", envOrDefault("KANJIDIC_PATH", "kanjidic2.xml.gz"), "path to the KANJIDIC2 file ($KANJIDIC_PATH)")
+	flags.BoolVar(&df.json, "json", false, "print results as JSON")
+
+	return flags, &df
+}
+
+func envOrDefault(name, value string) string {
+	if env := os.Getenv(name); env != "" {
+		return env
+	}
+
+	return value
+}
+
+// openDict opens a dictionary file, transparently decompressing it if it
+// is gzipped. The path "-" refers to standard input.
+func openDict(path string) (*dictFile, error) {
+	file := os.Stdin
+	if path != "-" {
+		var err error
+		if file, err = os.Open(path); err != nil {
+			return nil, err
+		}
+	}
+
+	df := &dictFile{Reader: bufio.NewReader(file), closers: []io.Closer{file}}
+
+	magic, err := df.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(df.Reader)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		df.Reader = bufio.NewReader(gz)
+		df.closers = append(df.closers, gz)
+	}
+
+	return df, nil
+}
+
+type dictFile struct {
+	*bufio.Reader
+	closers []io.Closer
+}
+
+func (f *dictFile) Close() error {
+	var err error
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		if e := f.closers[i].Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (f *dictFile) isSnapshot() bool {
+	prefix, _ := f.Peek(len(jmdict.SnapshotMagic))
+	return jmdict.IsSnapshot(prefix)
+}
+
+// isXML reports whether the file starts with markup, after any byte order
+// mark and white space, rather than with an EDICT2 line.
+func (f *dictFile) isXML() bool {
+	prefix, _ := f.Peek(512)
+	text := strings.TrimLeft(strings.TrimPrefix(string(prefix), "\ufeff"), " \t\r\n")
+	return strings.HasPrefix(text, "<")
+}
+
+// loadJmdict reads a dictionary from an XML file, an EDICT2 file or a
+// snapshot. Unless transform is set, coded information is kept as entity
+// names; EDICT2 files only hold entity names.
+func loadJmdict(path string, transform bool, options ...jmdict.LoadOption) (jmdict.Jmdict, error) {
+	reader, err := openDict(path)
+	if err != nil {
+		return jmdict.Jmdict{}, err
+	}
+	defer reader.Close()
+
+	var dict jmdict.Jmdict
+	switch {
+	case reader.isSnapshot():
+		if dict, err = jmdict.ReadJmdictSnapshot(reader); err == nil {
+			dict = jmdict.ApplyJmdictOptions(dict, options...)
+		}
+	case !reader.isXML():
+		dict, err = jmdict.LoadEdict2(reader, options...)
+	case transform:
+		dict, _, err = jmdict.LoadJmdict(reader, options...)
+	default:
+		dict, _, err = jmdict.LoadJmdictNoTransform(reader, options...)
+	}
+
+	if err != nil {
+		return dict, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return dict, nil
+}
+
+func loadJmnedict(path string, transform bool, options ...jmdict.LoadOption) (jmdict.Jmnedict, error) {
+	reader, err := openDict(path)
+	if err != nil {
+		return jmdict.Jmnedict{}, err
+	}
+	defer reader.Close()
+
+	var dic jmdict.Jmnedict
+	switch {
+	case reader.isSnapshot():
+		if dic, err = jmdict.ReadJmnedictSnapshot(reader); err == nil {
+			dic = jmdict.ApplyJmnedictOptions(dic, options...)
+		}
+	case transform:
+		dic, _, err = jmdict.LoadJmnedict(reader, options...)
+	default:
+		dic, _, err = jmdict.LoadJmnedictNoTransform(reader, options...)
+	}
+
+	if err != nil {
+		return dic, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return dic, nil
+}
+
+func loadKanjidic(path string, options ...jmdict.LoadOption) (jmdict.Kanjidic, error) {
+	reader, err := openDict(path)
+	if err != nil {
+		return jmdict.Kanjidic{}, err
+	}
+	defer reader.Close()
+
+	var dic jmdict.Kanjidic
+	if reader.isSnapshot() {
+		if dic, err = jmdict.ReadKanjidicSnapshot(reader); err == nil {
+			dic = jmdict.ApplyKanjidicOptions(dic, options...)
+		}
+	} else {
+		dic, err = jmdict.LoadKanjidic(reader, options...)
+	}
+
+	if err != nil {
+		return dic, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return dic, nil
+}
+
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	"foosoft.net/projects/jmdict"
+)
+
+func runServe(args []string) error {
+	flags, df := newFlagSet("serve")
+
+	var (
+		addr      string
+		transform bool
+	)
+
+	flags.StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	flags.BoolVar(&transform, "expand", false, "expand entity codes (e.g. v1) to their descriptions")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Dictionaries whose path is set to the empty string are not served.
+	var (
+		words *jmdict.JmdictIndex
+		names *jmdict.JmnedictIndex
+		kanji *jmdict.KanjiIndex
+	)
+
+	if df.jmdict != "" {
+		dict, err := loadJmdict(df.jmdict, transform)
+		if err != nil {
+			return err
+		}
+		words = jmdict.NewJmdictIndex(&dict)
+	}
+
+	if df.jmnedict != "" {
+		dic, err := loadJmnedict(df.jmnedict, transform)
+		if err != nil {
+			return err
+		}
+		names = jmdict.NewJmnedictIndex(&dic)
+	}
+
+	if df.kanjidic != "" {
+		dic, err := loadKanjidic(df.kanjidic)
+		if err != nil {
+			return err
+		}
+		kanji = jmdict.NewKanjiIndex(&dic)
+	}
+
+	if words == nil && names == nil && kanji == nil {
+		return errors.New("no dictionaries to serve")
+	}
+
+	log.Printf("listening on %s", addr)
+	return http.ListenAndServe(addr, jmdict.NewHandler(words, names, kanji))
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"foosoft.net/projects/jmdict"
+)
+
+func runStats(args []string) error {
+	flags, df := newFlagSet("stats")
+
+	var kind string
+	flags.StringVar(&kind, "type", "jmdict", "type of the dictionary: jmdict, jmnedict or kanjidic")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	switch kind {
+	case "jmdict":
+		dict, err := loadJmdict(df.jmdict, false)
+		if err != nil {
+			return err
+		}
+		stats := jmdict.CollectJmdictStats(dict)
+		if df.json {
+			return printJSON(stats)
+		}
+		writeJmdictStats(os.Stdout, &stats)
+	case "jmnedict":
+		dic, err := loadJmnedict(df.jmnedict, false)
+		if err != nil {
+			return err
+		}
+		stats := jmdict.CollectJmnedictStats(dic)
+		if df.json {
+			return printJSON(stats)
+		}
+		writeJmnedictStats(os.Stdout, &stats)
+	case "kanjidic":
+		dic, err := loadKanjidic(df.kanjidic)
+		if err != nil {
+			return err
+		}
+		stats := jmdict.CollectKanjidicStats(dic)
+		if df.json {
+			return printJSON(stats)
+		}
+		writeKanjidicStats(os.Stdout, &stats)
+	default:
+		return fmt.Errorf("unknown dictionary type %q", kind)
+	}
+
+	return nil
+}
+
+func writeJmdictStats(w io.Writer, stats *jmdict.JmdictStats) {
+	writeCount(w, "Created", stats.Created)
+	writeCount(w, "Entries", stats.Entries)
+	writeCount(w, "Kanji elements", stats.KanjiElements)
+	writeCount(w, "Reading elements", stats.ReadingElements)
+	writeCount(w, "Senses", stats.Senses)
+	writeCount(w, "Glosses", stats.Glosses)
+	writeHistogram(w, "Senses by language", stats.SensesByLanguage)
+	writeHistogram(w, "Glosses by language", stats.GlossesByLanguage)
+	writeHistogram(w, "Parts of speech", stats.PartsOfSpeech)
+	writeHistogram(w, "Misc", stats.Misc)
+	writeHistogram(w, "Fields", stats.Fields)
+	writeHistogram(w, "Dialects", stats.Dialects)
+	writeHistogram(w, "Priorities", stats.Priorities)
+}
+
+func writeJmnedictStats(w io.Writer, stats *jmdict.JmnedictStats) {
+	writeCount(w, "Created", stats.Created)
+	writeCount(w, "Entries", stats.Entries)
+	writeCount(w, "Kanji elements", stats.KanjiElements)
+	writeCount(w, "Reading elements", stats.ReadingElements)
+	writeCount(w, "Translations", stats.Translations)
+	writeHistogram(w, "Translations by language", stats.TranslationsByLanguage)
+	writeHistogram(w, "Name types", stats.NameTypes)
+}
+
+func writeKanjidicStats(w io.Writer, stats *jmdict.KanjidicStats) {
+	writeCount(w, "Created", stats.Created)
+	writeCount(w, "Characters", stats.Characters)
+	writeCount(w, "Nanori", stats.Nanori)
+	writeNumberedHistogram(w, "Grades (0 = none)", stats.Grades)
+	writeNumberedHistogram(w, "JLPT levels (0 = none)", stats.JlptLevels)
+	writeNumberedHistogram(w, "Stroke counts", stats.StrokeCounts)
+	writeHistogram(w, "Meanings by language", stats.MeaningsByLanguage)
+	writeHistogram(w, "Readings by type", stats.ReadingsByType)
+}
+
+func writeCount(w io.Writer, label string, value interface{}) {
+	if value != "" {
+		fmt.Fprintf(w, "%-20s %v\n", label+":", value)
+	}
+}
+
+// writeHistogram writes the counts in descending order.
+func writeHistogram(w io.Writer, label string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+
+	fmt.Fprintf(w, "\n%s:\n", label)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %-18s %d\n", key, counts[key])
+	}
+}
+
+// writeNumberedHistogram writes the counts in ascending order of the key.
+func writeNumberedHistogram(w io.Writer, label string, counts map[int]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	keys := make([]int, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	fmt.Fprintf(w, "\n%s:\n", label)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %-18d %d\n", key, counts[key])
+	}
+}
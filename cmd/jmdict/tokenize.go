@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"foosoft.net/projects/jmdict"
+)
+
+func runTokenize(args []string) error {
+	flags, df := newFlagSet("tokenize")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	dict, err := loadJmdict(df.jmdict, false)
+	if err != nil {
+		return err
+	}
+
+	tokens := jmdict.NewJmdictIndex(&dict).Tokenize(strings.Join(flags.Args(), " "))
+	if df.json {
+		return printJSON(tokens)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, token := range tokens {
+		sequences := make([]string, 0, len(token.Sequences))
+		for _, sequence := range token.Sequences {
+			sequences = append(sequences, strconv.Itoa(sequence))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", token.Surface, token.Term, strings.Join(token.Reasons, ", "), strings.Join(sequences, ","))
+	}
+
+	return w.Flush()
+}
@@ -4,22 +4,68 @@ import (
 	"encoding/xml"
 	"io"
 	"regexp"
+	"strings"
 )
 
-func parseDict(reader io.Reader, container interface{}, transform bool) (map[string]string, error) {
+type DictionaryInfo struct {
+	// The creation date of the file in international format (YYYY-MM-DD),
+	// taken from the "created" comment preceding the root element or,
+	// for KANJIDIC, from the header.
+	Created string
+
+	// The declarations of the internal DTD subset of the DOCTYPE
+	// declaration, along with its raw text.
+	DTD DTD
+
+	// The names of the elements declared in the DTD, in order of declaration.
+	Elements []string
+
+	// The number of entities declared in the DTD.
+	EntityCount int
+
+	// The text of all comments found outside of the root element.
+	Comments []string
+
+	// The languages kept by the WithLanguages load option, in order, or nil
+	// if the content in all languages was loaded.
+	Languages []string
+}
+
+var createdPattern = regexp.MustCompile(`created:\s*(\d{4}-\d{2}-\d{2})`)
+
+// elementHandler is invoked by parseDict for each child of the root
+// element, and must either decode or skip the element.
+type elementHandler func(decoder *xml.Decoder, element *xml.StartElement, info *DictionaryInfo) error
+
+func parseDict(reader io.Reader, transform bool, handler elementHandler) (DictionaryInfo, map[string]string, error) {
 	decoder := xml.NewDecoder(reader)
 
+	var info DictionaryInfo
 	var entities map[string]string
+	var inRoot bool
 	for {
-		token, _ := decoder.Token()
-		if token == nil {
+		token, err := decoder.Token()
+		if err == io.EOF {
 			break
+		} else if err != nil {
+			return info, nil, err
 		}
 
 		switch startElement := token.(type) {
 		case xml.Directive:
 			directive := token.(xml.Directive)
-			entities = parseEntities(&directive)
+			dtd, err := parseDoctype(&directive)
+			if err != nil {
+				return info, nil, err
+			}
+
+			entities = dtd.EntityMap()
+			info.DTD = *dtd
+			info.EntityCount = len(entities)
+			for _, element := range dtd.Elements {
+				info.Elements = append(info.Elements, element.Name)
+			}
+
 			if transform {
 				decoder.Entity = entities
 			} else {
@@ -28,24 +74,40 @@ func parseDict(reader io.Reader, container interface{}, transform bool) (map[str
 					decoder.Entity[k] = k
 				}
 			}
+		case xml.Comment:
+			if inRoot {
+				break
+			}
+
+			comment := strings.TrimSpace(string(startElement))
+			info.Comments = append(info.Comments, comment)
+			if match := createdPattern.FindStringSubmatch(comment); match != nil && info.Created == "" {
+				info.Created = match[1]
+			}
 		case xml.StartElement:
-			if err := decoder.DecodeElement(container, &startElement); err != nil {
-				return nil, err
+			if !inRoot {
+				inRoot = true
+				break
+			}
+
+			if err := handler(decoder, &startElement, &info); err != nil {
+				return info, nil, err
 			}
+		case xml.EndElement:
+			inRoot = false
 		}
 	}
 
-	return entities, nil
+	return info, entities, nil
 }
 
-func parseEntities(d *xml.Directive) map[string]string {
-	re := regexp.MustCompile("<!ENTITY\\s([0-9\\-A-z]+)\\s\"(.+)\">")
-	matches := re.FindAllStringSubmatch(string(*d), -1)
-
-	entities := make(map[string]string)
-	for _, match := range matches {
-		entities[match[1]] = match[2]
+func parseDoctype(d *xml.Directive) (*DTD, error) {
+	subset := string(*d)
+	start := strings.IndexByte(subset, '[')
+	end := strings.LastIndexByte(subset, ']')
+	if start < 0 || end < start {
+		return &DTD{}, nil
 	}
 
-	return entities
+	return ParseDTD(strings.TrimSpace(subset[start+1 : end]))
 }
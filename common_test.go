@@ -0,0 +1,51 @@
+package jmdict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadJmdictInfo(t *testing.T) {
+	dict, _, err := LoadJmdict(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := dict.Info
+	if info.Created != "2022-07-14" {
+		t.Errorf("Created = %q", info.Created)
+	}
+	if want := []string{"JMdict", "entry"}; !reflect.DeepEqual(info.Elements, want) {
+		t.Errorf("Elements = %v, want %v", info.Elements, want)
+	}
+	if want := []string{"JMdict created: 2022-07-14"}; !reflect.DeepEqual(info.Comments, want) {
+		t.Errorf("Comments = %v, want %v", info.Comments, want)
+	}
+	if !strings.HasPrefix(info.DTD.Source, "<!ELEMENT JMdict") {
+		t.Errorf("DTD source = %q", info.DTD.Source)
+	}
+	if description := info.DTD.EntityMap()["v1"]; description != "Ichidan verb" {
+		t.Errorf("description of v1 = %q", description)
+	}
+}
+
+func TestLoadKanjidicInfo(t *testing.T) {
+	const data = `<?xml version="1.0" encoding="UTF-8"?>
+<kanjidic2>
+<header>
+<file_version>4</file_version>
+<database_version>2022-195</database_version>
+<date_of_creation>2022-07-14</date_of_creation>
+</header>
+</kanjidic2>
+`
+
+	dic, err := LoadKanjidic(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dic.Info.Created != "2022-07-14" {
+		t.Errorf("Created = %q", dic.Info.Created)
+	}
+}
@@ -0,0 +1,73 @@
+package jmdict
+
+type dictDefaults struct {
+	glossLanguage       string
+	sourceLanguage      string
+	sourceType          string
+	translationLanguage string
+}
+
+// newDictDefaults looks up implied attribute values in the DTD of the file
+// being loaded, falling back to the values documented by the EDRDG for
+// files which do not declare them.
+func newDictDefaults(dtd *DTD) *dictDefaults {
+	lookup := func(fallback string, element, attribute string, alternates ...string) string {
+		for _, e := range append([]string{element}, alternates...) {
+			if value, ok := dtd.AttributeDefault(e, attribute); ok {
+				return value
+			}
+		}
+
+		return fallback
+	}
+
+	return &dictDefaults{
+		glossLanguage:  lookup("eng", "gloss", "xml:lang"),
+		sourceLanguage: lookup("eng", "lsource", "xml:lang"),
+		sourceType:     lookup("full", "lsource", "ls_type"),
+
+		// JMnedict declares the language on trans_det, while it is
+		// recorded per trans element here.
+		translationLanguage: lookup("eng", "trans", "xml:lang", "trans_det"),
+	}
+}
+
+func impliedValue(value string) *string {
+	return &value
+}
+
+func (d *dictDefaults) applyJmdict(entry *JmdictEntry) {
+	for i := range entry.Sense {
+		sense := &entry.Sense[i]
+
+		for j := range sense.Glossary {
+			gloss := &sense.Glossary[j]
+			if gloss.Language == nil {
+				gloss.Language = impliedValue(d.glossLanguage)
+				gloss.LanguageImplied = true
+			}
+		}
+
+		for j := range sense.SourceLanguages {
+			source := &sense.SourceLanguages[j]
+			if source.Language == nil {
+				source.Language = impliedValue(d.sourceLanguage)
+				source.LanguageImplied = true
+			}
+			if source.Type == nil {
+				source.Type = impliedValue(d.sourceType)
+				source.TypeImplied = true
+			}
+		}
+	}
+}
+
+func (d *dictDefaults) applyJmnedict(entry *JmnedictEntry) {
+	for i := range entry.Translations {
+		trans := &entry.Translations[i]
+		if trans.Language == nil {
+			trans.Language = impliedValue(d.translationLanguage)
+			trans.LanguageImplied = true
+		}
+	}
+}
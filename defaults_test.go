@@ -0,0 +1,88 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []LoadOption
+		language []string
+		implied  []bool
+	}{
+		{"without defaults", nil, []string{"", "ger"}, []bool{false, false}},
+		{"with defaults", []LoadOption{WithDefaults()}, []string{"eng", "ger"}, []bool{true, false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dict, _, err := LoadJmdict(strings.NewReader(testJmdictXML), test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, gloss := range dict.Entries[0].Sense[0].Glossary {
+				var language string
+				if gloss.Language != nil {
+					language = *gloss.Language
+				}
+
+				if language != test.language[i] || gloss.LanguageImplied != test.implied[i] {
+					t.Errorf("gloss %q has language %q (implied %v), want %q (%v)",
+						gloss.Content, language, gloss.LanguageImplied, test.language[i], test.implied[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDictDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		subset string
+		want   dictDefaults
+	}{
+		{
+			name: "documented values",
+			want: dictDefaults{glossLanguage: "eng", sourceLanguage: "eng", sourceType: "full", translationLanguage: "eng"},
+		},
+		{
+			name: "declared values",
+			subset: `<!ATTLIST gloss xml:lang CDATA "ger">
+<!ATTLIST lsource xml:lang CDATA "fre" ls_type CDATA "part">
+<!ATTLIST trans_det xml:lang CDATA "dut">`,
+			want: dictDefaults{glossLanguage: "ger", sourceLanguage: "fre", sourceType: "part", translationLanguage: "dut"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dtd, err := ParseDTD(test.subset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := newDictDefaults(dtd); *got != test.want {
+				t.Errorf("got %+v, want %+v", *got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithDefaultsJSON(t *testing.T) {
+	dict, _, err := LoadJmdict(strings.NewReader(testJmdictXML), WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(dict.Entries[0].Sense[0].Glossary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Implied") {
+		t.Errorf("JSON %s contains the implied flags", data)
+	}
+}
@@ -0,0 +1,313 @@
+package jmdict
+
+import "strings"
+
+// wordClass is a set of the conjugation classes of words, as bits.
+type wordClass int
+
+const (
+	classIchidan wordClass = 1 << iota
+	classGodan
+	classKuru
+	classSuru
+	classAdjective
+
+	// The polite auxiliary ます, which no dictionary form belongs to.
+	classMasu
+)
+
+// Deinflection is a possible dictionary form of an inflected word.
+type Deinflection struct {
+	// The dictionary form, such as 食べる for 食べなかった.
+	Term string
+
+	// The inflections undone to get the dictionary form, innermost first
+	// ("negative", "past"). Empty if the word was not inflected.
+	Reasons []string
+
+	// The conjugation classes the dictionary form may belong to, or zero if
+	// the word was not inflected and may be any word.
+	classes wordClass
+}
+
+// inflection undoes an inflection by replacing the suffix kanaIn of a
+// word of any of the classes rulesIn (or of an uninflected word when
+// rulesIn is zero) by kanaOut, giving a word of the classes rulesOut.
+type inflection struct {
+	kanaIn   string
+	kanaOut  string
+	rulesIn  wordClass
+	rulesOut wordClass
+	reason   string
+}
+
+// conjugation holds the inflected stems of a verb class or irregular verb,
+// each of which takes the place of the dictionary form ending out.
+type conjugation struct {
+	out   string
+	class wordClass
+
+	irrealis     string // before ない (書か, 食べ)
+	continuative string // before ます (書き, 食べ)
+	hypothetical string // before ば (書け, 食べれ)
+	volitional   string
+	imperative   []string
+	te           string
+	ta           string
+	passive      string
+	causative    string
+	potential    string
+}
+
+var godanRows = []struct{ out, a, i, e, o, te, ta string }{
+	{"う", "わ", "い", "え", "お", "って", "った"},
+	{"く", "か", "き", "け", "こ", "いて", "いた"},
+	{"ぐ", "が", "ぎ", "げ", "ご", "いで", "いだ"},
+	{"す", "さ", "し", "せ", "そ", "して", "した"},
+	{"つ", "た", "ち", "て", "と", "って", "った"},
+	{"ぬ", "な", "に", "ね", "の", "んで", "んだ"},
+	{"ぶ", "ば", "び", "べ", "ぼ", "んで", "んだ"},
+	{"む", "ま", "み", "め", "も", "んで", "んだ"},
+	{"る", "ら", "り", "れ", "ろ", "って", "った"},
+
+	// 行く is the only く verb with って and った.
+	{"行く", "行か", "行き", "行け", "行こ", "行って", "行った"},
+	{"いく", "いか", "いき", "いけ", "いこ", "いって", "いった"},
+}
+
+func conjugations() []conjugation {
+	var result []conjugation
+	for _, row := range godanRows {
+		result = append(result, conjugation{
+			out:          row.out,
+			class:        classGodan,
+			irrealis:     row.a,
+			continuative: row.i,
+			hypothetical: row.e,
+			volitional:   row.o + "う",
+			imperative:   []string{row.e},
+			te:           row.te,
+			ta:           row.ta,
+			passive:      row.a + "れる",
+			causative:    row.a + "せる",
+			potential:    row.e + "る",
+		})
+	}
+
+	// The passive of ichidan verbs is also their potential form, and is
+	// listed as the former.
+	result = append(result, conjugation{
+		out:          "る",
+		class:        classIchidan,
+		hypothetical: "れ",
+		volitional:   "よう",
+		imperative:   []string{"ろ", "よ"},
+		te:           "て",
+		ta:           "た",
+		passive:      "られる",
+		causative:    "させる",
+	})
+
+	for _, stem := range []string{"", "来"} {
+		ku, ko, ki := "く", "こ", "き"
+		if stem != "" {
+			ku, ko, ki = stem, stem, stem
+		}
+
+		result = append(result, conjugation{
+			out:          ku + "る",
+			class:        classKuru,
+			irrealis:     ko,
+			continuative: ki,
+			hypothetical: ku + "れ",
+			volitional:   ko + "よう",
+			imperative:   []string{ko + "い"},
+			te:           ki + "て",
+			ta:           ki + "た",
+			passive:      ko + "られる",
+			causative:    ko + "させる",
+		})
+	}
+
+	result = append(result, conjugation{
+		out:          "する",
+		class:        classSuru,
+		irrealis:     "し",
+		continuative: "し",
+		hypothetical: "すれ",
+		volitional:   "しよう",
+		imperative:   []string{"しろ", "せよ"},
+		te:           "して",
+		ta:           "した",
+		passive:      "される",
+		causative:    "させる",
+	})
+
+	return result
+}
+
+var inflections = buildInflections()
+
+func buildInflections() []inflection {
+	var rules []inflection
+	add := func(kanaIn, kanaOut string, rulesIn, rulesOut wordClass, reason string) {
+		if kanaIn != "" {
+			rules = append(rules, inflection{kanaIn: kanaIn, kanaOut: kanaOut, rulesIn: rulesIn, rulesOut: rulesOut, reason: reason})
+		}
+	}
+
+	for _, c := range conjugations() {
+		add(c.irrealis+"ない", c.out, classAdjective, c.class, "negative")
+		add(c.irrealis+"ず", c.out, 0, c.class, "-zu")
+		add(c.continuative+"ます", c.out, classMasu, c.class, "polite")
+		add(c.continuative+"たい", c.out, classAdjective, c.class, "-tai")
+		add(c.continuative+"そう", c.out, 0, c.class, "-sou")
+		add(c.continuative+"すぎる", c.out, classIchidan, c.class, "-sugiru")
+		add(c.continuative+"ながら", c.out, 0, c.class, "-nagara")
+		if c.continuative != "" {
+			add(c.continuative, c.out, 0, c.class, "masu stem")
+		}
+		add(c.hypothetical+"ば", c.out, 0, c.class, "-ba")
+		add(c.volitional, c.out, 0, c.class, "volitional")
+		for _, imperative := range c.imperative {
+			add(imperative, c.out, 0, c.class, "imperative")
+		}
+		add(c.te, c.out, 0, c.class, "-te")
+		add(c.ta, c.out, 0, c.class, "past")
+		add(c.ta+"ら", c.out, 0, c.class, "-tara")
+		add(c.ta+"り", c.out, 0, c.class, "-tari")
+		add(c.te+"いる", c.out, classIchidan, c.class, "progressive")
+		add(c.te+"る", c.out, classIchidan, c.class, "progressive")
+		add(c.te+"しまう", c.out, classGodan, c.class, "-shimau")
+		if strings.HasSuffix(c.te, "て") {
+			add(strings.TrimSuffix(c.te, "て")+"ちゃう", c.out, classGodan, c.class, "-chau")
+		} else {
+			add(strings.TrimSuffix(c.te, "で")+"じゃう", c.out, classGodan, c.class, "-chau")
+		}
+		add(c.passive, c.out, classIchidan, c.class, "passive")
+		add(c.causative, c.out, classIchidan, c.class, "causative")
+		add(c.potential, c.out, classIchidan, c.class, "potential")
+	}
+
+	add("せず", "する", 0, classSuru, "-zu")
+
+	add("ました", "ます", 0, classMasu, "past")
+	add("ません", "ます", 0, classMasu, "negative")
+	add("ませんでした", "ます", 0, classMasu, "past negative")
+	add("ましょう", "ます", 0, classMasu, "volitional")
+	add("まして", "ます", 0, classMasu, "-te")
+	add("ましたら", "ます", 0, classMasu, "-tara")
+
+	add("くない", "い", classAdjective, classAdjective, "negative")
+	add("かった", "い", 0, classAdjective, "past")
+	add("かったら", "い", 0, classAdjective, "-tara")
+	add("かったり", "い", 0, classAdjective, "-tari")
+	add("くて", "い", 0, classAdjective, "-te")
+	add("く", "い", 0, classAdjective, "adverb")
+	add("ければ", "い", 0, classAdjective, "-ba")
+	add("さ", "い", 0, classAdjective, "noun")
+	add("そう", "い", 0, classAdjective, "-sou")
+	add("すぎる", "い", classIchidan, classAdjective, "-sugiru")
+
+	return rules
+}
+
+// Deinflect returns the possible dictionary forms of the word, starting
+// with the word itself, then those which undo the fewest inflections. Most
+// of the forms are not words; they are meant to be looked up, keeping the
+// entries which Matches.
+func Deinflect(word string) []Deinflection {
+	type key struct {
+		term    string
+		classes wordClass
+	}
+
+	results := []Deinflection{{Term: word}}
+	seen := map[key]bool{{term: word}: true}
+
+	for i := 0; i < len(results); i++ {
+		d := results[i]
+		for _, rule := range inflections {
+			// Rules apply to uninflected words regardless of class, which
+			// is the only way rules without rulesIn apply.
+			if d.classes != 0 && d.classes&rule.rulesIn == 0 {
+				continue
+			}
+			if !strings.HasSuffix(d.Term, rule.kanaIn) {
+				continue
+			}
+
+			term := strings.TrimSuffix(d.Term, rule.kanaIn) + rule.kanaOut
+			k := key{term: term, classes: rule.rulesOut}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			results = append(results, Deinflection{
+				Term:    term,
+				Reasons: append([]string{rule.reason}, d.Reasons...),
+				classes: rule.rulesOut,
+			})
+		}
+	}
+
+	return results
+}
+
+// Matches reports whether the entry may be the dictionary form, that is,
+// whether a sense of the entry is of a part of speech inflecting the way
+// the word was. Any entry matches an uninflected word. Parts of speech are
+// recognized by entity code or by description, as the dictionary was
+// loaded.
+func (d *Deinflection) Matches(entry *JmdictEntry) bool {
+	if d.classes == 0 {
+		return true
+	}
+
+	for _, sense := range entry.Sense {
+		for _, pos := range sense.PartsOfSpeech {
+			if partOfSpeechClass(pos)&d.classes != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// partOfSpeechClass returns the conjugation class of a JMdict part of
+// speech entity code or description, or zero if the part of speech does
+// not inflect.
+func partOfSpeechClass(pos string) wordClass {
+	switch pos {
+	case "v1", "v1-s", "Ichidan verb", "Ichidan verb - kureru special class":
+		return classIchidan
+	case "vk", "Kuru verb - special class":
+		return classKuru
+	case "vs-i", "vs-s", "suru verb - included", "suru verb - special class":
+		return classSuru
+	case "adj-i", "adj-ix", "adjective (keiyoushi)", "adjective (keiyoushi) - yoi/ii class":
+		return classAdjective
+	}
+
+	if strings.HasPrefix(pos, "v5") || strings.HasPrefix(pos, "Godan verb") {
+		return classGodan
+	}
+
+	return 0
+}
+
+// takesSuru reports whether the entry is a noun taking the auxiliary verb
+// する (勉強 in 勉強する).
+func takesSuru(entry *JmdictEntry) bool {
+	for _, sense := range entry.Sense {
+		for _, pos := range sense.PartsOfSpeech {
+			if pos == "vs" || pos == "noun or participle which takes the aux. verb suru" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
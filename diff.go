@@ -0,0 +1,491 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type DiffChangeKind string
+
+const (
+	DiffAdded     DiffChangeKind = "added"
+	DiffRemoved   DiffChangeKind = "removed"
+	DiffModified  DiffChangeKind = "modified"
+	DiffReordered DiffChangeKind = "reordered"
+)
+
+type JmdictDiff struct {
+	// Entries whose sequence number only appears in the newer dictionary.
+	Added []JmdictEntry `json:"added,omitempty"`
+
+	// Entries whose sequence number only appears in the older dictionary.
+	Removed []JmdictEntry `json:"removed,omitempty"`
+
+	// Entries present in both dictionaries with differing content.
+	Modified []JmdictEntryDiff `json:"modified,omitempty"`
+}
+
+type JmdictEntryDiff struct {
+	Sequence int `json:"sequence"`
+
+	// The headword of the entry in the newer dictionary, for display.
+	Headword string `json:"headword"`
+
+	Changes []JmdictChange `json:"changes"`
+}
+
+type JmdictChange struct {
+	// The location of the change within the entry. Kanji and reading
+	// elements are addressed by their text, senses by their position in
+	// the newer entry, e.g. "Kanji[食べる].Priorities" or "Sense[1].Glossary".
+	// Repeated text is numbered from its second occurrence ("Kanji[食べる#2]").
+	Field string `json:"field"`
+
+	Kind DiffChangeKind `json:"kind"`
+
+	// The previous and current values of the field. Old is nil for added
+	// values and New is nil for removed values.
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// DiffJmdict compares two releases of JMdict, matching entries by their
+// sequence number. Results are ordered by sequence number.
+func DiffJmdict(before, after Jmdict) JmdictDiff {
+	beforeEntries := make(map[int]*JmdictEntry)
+	for i := range before.Entries {
+		beforeEntries[before.Entries[i].Sequence] = &before.Entries[i]
+	}
+
+	afterEntries := make(map[int]*JmdictEntry)
+	for i := range after.Entries {
+		afterEntries[after.Entries[i].Sequence] = &after.Entries[i]
+	}
+
+	var diff JmdictDiff
+	for seq, beforeEntry := range beforeEntries {
+		if _, ok := afterEntries[seq]; !ok {
+			diff.Removed = append(diff.Removed, *beforeEntry)
+		}
+	}
+
+	for seq, afterEntry := range afterEntries {
+		beforeEntry, ok := beforeEntries[seq]
+		if !ok {
+			diff.Added = append(diff.Added, *afterEntry)
+			continue
+		}
+
+		if changes := diffJmdictEntry(beforeEntry, afterEntry); len(changes) > 0 {
+			diff.Modified = append(diff.Modified, JmdictEntryDiff{
+				Sequence: seq,
+				Headword: jmdictHeadword(afterEntry),
+				Changes:  changes,
+			})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Sequence < diff.Added[j].Sequence })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Sequence < diff.Removed[j].Sequence })
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].Sequence < diff.Modified[j].Sequence })
+
+	return diff
+}
+
+func (d *JmdictDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// WriteReport writes the differences in a human-readable form, one line
+// per added or removed entry followed by the modified entries and an
+// indented line for each of their changes.
+func (d *JmdictDiff) WriteReport(writer io.Writer) error {
+	for i := range d.Added {
+		entry := &d.Added[i]
+		if _, err := fmt.Fprintf(writer, "+ %d %s\n", entry.Sequence, jmdictHeadword(entry)); err != nil {
+			return err
+		}
+	}
+
+	for i := range d.Removed {
+		entry := &d.Removed[i]
+		if _, err := fmt.Fprintf(writer, "- %d %s\n", entry.Sequence, jmdictHeadword(entry)); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range d.Modified {
+		if _, err := fmt.Fprintf(writer, "~ %d %s\n", entry.Sequence, entry.Headword); err != nil {
+			return err
+		}
+
+		for _, change := range entry.Changes {
+			var line string
+			switch change.Kind {
+			case DiffAdded:
+				line = fmt.Sprintf("%s: added %s", change.Field, describeDiffValue(change.New))
+			case DiffRemoved:
+				line = fmt.Sprintf("%s: removed %s", change.Field, describeDiffValue(change.Old))
+			default:
+				line = fmt.Sprintf("%s: %s %s -> %s", change.Field, change.Kind, describeDiffValue(change.Old), describeDiffValue(change.New))
+			}
+
+			if _, err := fmt.Fprintf(writer, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func jmdictHeadword(entry *JmdictEntry) string {
+	var reading string
+	if len(entry.Readings) > 0 {
+		reading = entry.Readings[0].Reading
+	}
+
+	if len(entry.Kanji) > 0 {
+		return fmt.Sprintf("%s【%s】", entry.Kanji[0].Expression, reading)
+	}
+
+	return reading
+}
+
+func diffJmdictEntry(before, after *JmdictEntry) []JmdictChange {
+	var changes []JmdictChange
+	changes = append(changes, diffElements("Kanji", before.Kanji, after.Kanji, func(k *JmdictKanji) string { return k.Expression })...)
+	changes = append(changes, diffElements("Readings", before.Readings, after.Readings, func(r *JmdictReading) string { return r.Reading })...)
+	return append(changes, diffSenses(before.Sense, after.Sense)...)
+}
+
+// diffElements compares kanji or reading elements, matching them by their
+// text as given by the key function.
+func diffElements[E any](field string, before, after []E, text func(*E) string) []JmdictChange {
+	beforeKeys := elementKeys(before, text)
+	afterKeys := elementKeys(after, text)
+
+	beforeIndices := make(map[string]int)
+	for i, key := range beforeKeys {
+		beforeIndices[key] = i
+	}
+
+	afterIndices := make(map[string]int)
+	for i, key := range afterKeys {
+		afterIndices[key] = i
+	}
+
+	var changes []JmdictChange
+	for i, key := range afterKeys {
+		if j, ok := beforeIndices[key]; ok {
+			changes = append(changes, diffFields(fmt.Sprintf("%s[%s]", field, key), &before[j], &after[i])...)
+		} else {
+			changes = append(changes, JmdictChange{Field: field, Kind: DiffAdded, New: after[i]})
+		}
+	}
+
+	for i, key := range beforeKeys {
+		if _, ok := afterIndices[key]; !ok {
+			changes = append(changes, JmdictChange{Field: field, Kind: DiffRemoved, Old: before[i]})
+		}
+	}
+
+	if change, ok := diffOrder(field, beforeKeys, afterKeys); ok {
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// elementKeys returns the text of each element, with the number of the
+// occurrence appended to repeated text ("食べる", "食べる#2"), so that
+// duplicate elements are matched in order rather than overwriting each
+// other.
+func elementKeys[E any](elements []E, text func(*E) string) []string {
+	occurrences := make(map[string]int)
+	keys := make([]string, len(elements))
+	for i := range elements {
+		key := text(&elements[i])
+		occurrences[key]++
+		if n := occurrences[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// diffSenses first matches the senses which are unchanged, keeping their
+// relative order, so that inserting or removing a sense does not make the
+// senses after it differ. The unmatched senses between two matched ones
+// are compared by position. When the newer entry contains exactly the same
+// senses in a different order, this is reported as a single reordering.
+func diffSenses(before, after []JmdictSense) []JmdictChange {
+	if len(before) == len(after) && !diffEqual(reflect.ValueOf(before), reflect.ValueOf(after)) {
+		if order, ok := senseOrder(before, after); ok {
+			identity := make([]int, len(order))
+			for i := range identity {
+				identity[i] = i
+			}
+
+			return []JmdictChange{{Field: "Sense", Kind: DiffReordered, Old: identity, New: order}}
+		}
+	}
+
+	var changes []JmdictChange
+	i, j := 0, 0
+	for _, match := range matchSenses(before, after) {
+		changes = append(changes, diffSenseRange(before, after, i, match[0], j, match[1])...)
+		i, j = match[0]+1, match[1]+1
+	}
+
+	return append(changes, diffSenseRange(before, after, i, len(before), j, len(after))...)
+}
+
+// diffSenseRange compares the unmatched senses before[i:iEnd] and
+// after[j:jEnd]. Added and modified senses are addressed by their position
+// in the newer entry, removed senses by their position in the older one.
+func diffSenseRange(before, after []JmdictSense, i, iEnd, j, jEnd int) []JmdictChange {
+	var changes []JmdictChange
+	for ; i < iEnd && j < jEnd; i, j = i+1, j+1 {
+		changes = append(changes, diffFields(fmt.Sprintf("Sense[%d]", j), &before[i], &after[j])...)
+	}
+
+	for ; j < jEnd; j++ {
+		changes = append(changes, JmdictChange{Field: fmt.Sprintf("Sense[%d]", j), Kind: DiffAdded, New: after[j]})
+	}
+
+	for ; i < iEnd; i++ {
+		changes = append(changes, JmdictChange{Field: fmt.Sprintf("Sense[%d]", i), Kind: DiffRemoved, Old: before[i]})
+	}
+
+	return changes
+}
+
+// matchSenses returns the positions in both entries of the longest common
+// subsequence of identical senses.
+func matchSenses(before, after []JmdictSense) [][2]int {
+	// lengths[i][j] is the length of the longest common subsequence of
+	// before[i:] and after[j:].
+	lengths := make([][]int, len(before)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(after)+1)
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if diffEqual(reflect.ValueOf(before[i]), reflect.ValueOf(after[j])) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var matches [][2]int
+	for i, j := 0, 0; i < len(before) && j < len(after); {
+		switch {
+		case diffEqual(reflect.ValueOf(before[i]), reflect.ValueOf(after[j])):
+			matches = append(matches, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
+// senseOrder returns, for each sense of the newer entry, the position of
+// the identical sense in the older entry.
+func senseOrder(before, after []JmdictSense) ([]int, bool) {
+	used := make([]bool, len(before))
+	order := make([]int, len(after))
+
+	for i := range after {
+		found := false
+		for j := range before {
+			if !used[j] && diffEqual(reflect.ValueOf(before[j]), reflect.ValueOf(after[i])) {
+				used[j] = true
+				order[i] = j
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	return order, true
+}
+
+func diffOrder(field string, before, after []string) (JmdictChange, bool) {
+	position := make(map[string]int)
+	for i, value := range after {
+		position[value] = i
+	}
+
+	var beforeCommon, afterCommon []string
+	for _, value := range before {
+		if _, ok := position[value]; ok {
+			beforeCommon = append(beforeCommon, value)
+		}
+	}
+
+	common := make(map[string]bool)
+	for _, value := range beforeCommon {
+		common[value] = true
+	}
+
+	for _, value := range after {
+		if common[value] {
+			afterCommon = append(afterCommon, value)
+		}
+	}
+
+	if reflect.DeepEqual(beforeCommon, afterCommon) {
+		return JmdictChange{}, false
+	}
+
+	return JmdictChange{Field: field, Kind: DiffReordered, Old: beforeCommon, New: afterCommon}, true
+}
+
+// diffFields reports each exported struct field that differs between two
+// values of the same type, as compared by diffEqual.
+func diffFields(prefix string, before, after interface{}) []JmdictChange {
+	beforeValue := reflect.ValueOf(before).Elem()
+	afterValue := reflect.ValueOf(after).Elem()
+
+	var changes []JmdictChange
+	for i := 0; i < beforeValue.NumField(); i++ {
+		field := beforeValue.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("xml") == "-" {
+			continue
+		}
+
+		o := beforeValue.Field(i)
+		n := afterValue.Field(i)
+		if !diffEqual(o, n) {
+			changes = append(changes, JmdictChange{
+				Field: prefix + "." + field.Name,
+				Kind:  DiffModified,
+				Old:   o.Interface(),
+				New:   n.Interface(),
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffEqual reports whether two values of the same type are deeply equal,
+// ignoring the struct fields which are not part of the XML form, such as
+// the flags recording that an attribute value was implied. Nil and empty
+// slices are equal, as they are written the same way.
+func diffEqual(before, after reflect.Value) bool {
+	switch before.Kind() {
+	case reflect.Pointer:
+		if before.IsNil() || after.IsNil() {
+			return before.IsNil() == after.IsNil()
+		}
+		return diffEqual(before.Elem(), after.Elem())
+	case reflect.Slice:
+		if before.Len() != after.Len() {
+			return false
+		}
+		for i := 0; i < before.Len(); i++ {
+			if !diffEqual(before.Index(i), after.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < before.NumField(); i++ {
+			field := before.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !diffEqual(before.Field(i), after.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(before.Interface(), after.Interface())
+	}
+}
+
+func describeDiffValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case *string:
+		if v == nil {
+			return "(none)"
+		}
+		return fmt.Sprintf("%q", *v)
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	case []int:
+		return fmt.Sprint(v)
+	case JmdictKanji:
+		return v.Expression
+	case JmdictReading:
+		return v.Reading
+	case JmdictSense:
+		if len(v.PartsOfSpeech) == 0 {
+			return describeDiffValue(v.Glossary)
+		}
+		return "(" + strings.Join(v.PartsOfSpeech, ", ") + ") " + describeDiffValue(v.Glossary)
+	case []JmdictGlossary:
+		var parts []string
+		for _, gloss := range v {
+			parts = append(parts, describeDiffAttributes(gloss.Content, gloss.Language, gloss.Type, gloss.Gender))
+		}
+		return "[" + strings.Join(parts, "; ") + "]"
+	case []JmdictSource:
+		var parts []string
+		for _, source := range v {
+			parts = append(parts, describeDiffAttributes(source.Content, source.Language, source.Type, &source.Wasei))
+		}
+		return "[" + strings.Join(parts, "; ") + "]"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+// describeDiffAttributes describes the content of an element followed by
+// the values of its attributes which are present, so that changes to the
+// attributes alone are visible.
+func describeDiffAttributes(content string, attrs ...*string) string {
+	var values []string
+	for _, attr := range attrs {
+		if attr != nil && *attr != "" {
+			values = append(values, *attr)
+		}
+	}
+
+	if len(values) == 0 {
+		return content
+	}
+
+	return content + " (" + strings.Join(values, ", ") + ")"
+}
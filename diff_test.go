@@ -0,0 +1,180 @@
+package jmdict
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDiffJmdict(t *testing.T) {
+	before := Jmdict{Entries: []JmdictEntry{
+		testEntry(1, "食べる", "たべる", testSense("v1", "to eat")),
+		testEntry(2, "学校", "がっこう", testSense("n", "school")),
+		testEntry(3, "", "は", testSense("prt", "topic marker")),
+	}}
+	after := Jmdict{Entries: []JmdictEntry{
+		testEntry(1, "食べる,喰べる", "たべる", testSense("v1", "to eat")),
+		testEntry(3, "", "は", testSense("prt", "topic marker particle")),
+		testEntry(4, "行く", "いく", testSense("v5k-s", "to go")),
+	}}
+
+	diff := DiffJmdict(before, after)
+
+	var added, removed []int
+	for _, entry := range diff.Added {
+		added = append(added, entry.Sequence)
+	}
+	for _, entry := range diff.Removed {
+		removed = append(removed, entry.Sequence)
+	}
+	if !reflect.DeepEqual(added, []int{4}) || !reflect.DeepEqual(removed, []int{2}) {
+		t.Errorf("added %v and removed %v, want [4] and [2]", added, removed)
+	}
+
+	var report bytes.Buffer
+	if err := diff.WriteReport(&report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "+ 4 行く【いく】\n" +
+		"- 2 学校【がっこう】\n" +
+		"~ 1 食べる【たべる】\n" +
+		"    Kanji: added 喰べる\n" +
+		"~ 3 は\n" +
+		"    Sense[0].Glossary: modified [topic marker] -> [topic marker particle]\n"
+	if got := report.String(); got != want {
+		t.Errorf("report:\n%s\nwant:\n%s", got, want)
+	}
+
+	if same := DiffJmdict(before, before); !same.Empty() {
+		t.Errorf("diff of a dictionary with itself = %+v", same)
+	}
+}
+
+func TestDiffJmdictEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		before JmdictEntry
+		after  JmdictEntry
+		fields []string
+		kinds  []DiffChangeKind
+	}{
+		{
+			name:   "reading removed",
+			before: testEntry(1, "", "たべる,たぶ", testSense("v1", "to eat")),
+			after:  testEntry(1, "", "たべる", testSense("v1", "to eat")),
+			fields: []string{"Readings"},
+			kinds:  []DiffChangeKind{DiffRemoved},
+		},
+		{
+			name:   "kanji reordered",
+			before: testEntry(1, "食べる,喰べる", "たべる", testSense("v1", "to eat")),
+			after:  testEntry(1, "喰べる,食べる", "たべる", testSense("v1", "to eat")),
+			fields: []string{"Kanji"},
+			kinds:  []DiffChangeKind{DiffReordered},
+		},
+		{
+			name:   "senses reordered",
+			before: testEntry(1, "", "たべる", testSense("v1", "to eat"), testSense("v1", "to live on")),
+			after:  testEntry(1, "", "たべる", testSense("v1", "to live on"), testSense("v1", "to eat")),
+			fields: []string{"Sense"},
+			kinds:  []DiffChangeKind{DiffReordered},
+		},
+		{
+			name:   "part of speech changed",
+			before: testEntry(1, "", "たべる", testSense("v1", "to eat")),
+			after:  testEntry(1, "", "たべる", testSense("v1,vt", "to eat")),
+			fields: []string{"Sense[0].PartsOfSpeech"},
+			kinds:  []DiffChangeKind{DiffModified},
+		},
+		{
+			name: "duplicate reading changed",
+			before: func() JmdictEntry {
+				entry := testEntry(1, "", "たべる,たべる", testSense("v1", "to eat"))
+				entry.Readings[1].Priorities = []string{"ichi1"}
+				return entry
+			}(),
+			after:  testEntry(1, "", "たべる,たべる", testSense("v1", "to eat")),
+			fields: []string{"Readings[たべる#2].Priorities"},
+			kinds:  []DiffChangeKind{DiffModified},
+		},
+		{
+			name:   "duplicate kanji removed",
+			before: testEntry(1, "食べる,食べる", "たべる", testSense("v1", "to eat")),
+			after:  testEntry(1, "食べる", "たべる", testSense("v1", "to eat")),
+			fields: []string{"Kanji"},
+			kinds:  []DiffChangeKind{DiffRemoved},
+		},
+		{
+			name:   "sense inserted",
+			before: testEntry(1, "", "たべる", testSense("v1", "to eat"), testSense("v1", "to live on")),
+			after:  testEntry(1, "", "たべる", testSense("v1", "to consume"), testSense("v1", "to eat"), testSense("v1", "to live on")),
+			fields: []string{"Sense[0]"},
+			kinds:  []DiffChangeKind{DiffAdded},
+		},
+		{
+			name:   "sense removed and another changed",
+			before: testEntry(1, "", "たべる", testSense("v1", "to eat"), testSense("v1", "to live on"), testSense("v1", "to bite")),
+			after:  testEntry(1, "", "たべる", testSense("v1", "to live on"), testSense("v1,vt", "to bite")),
+			fields: []string{"Sense[0]", "Sense[1].PartsOfSpeech"},
+			kinds:  []DiffChangeKind{DiffRemoved, DiffModified},
+		},
+		{
+			name:   "sense added",
+			before: testEntry(1, "", "たべる", testSense("v1", "to eat")),
+			after:  testEntry(1, "", "たべる", testSense("v1", "to eat"), testSense("v1", "to live on")),
+			fields: []string{"Sense[1]"},
+			kinds:  []DiffChangeKind{DiffAdded},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fields []string
+			var kinds []DiffChangeKind
+			for _, change := range diffJmdictEntry(&test.before, &test.after) {
+				fields = append(fields, change.Field)
+				kinds = append(kinds, change.Kind)
+			}
+
+			if !reflect.DeepEqual(fields, test.fields) || !reflect.DeepEqual(kinds, test.kinds) {
+				t.Errorf("changes %v %v, want %v %v", fields, kinds, test.fields, test.kinds)
+			}
+		})
+	}
+}
+
+func TestDescribeDiffValue(t *testing.T) {
+	ger, lit := "ger", "lit"
+	sense := testSense("v1", "to eat", "essen")
+	sense.Glossary[1].Language = &ger
+	sense.Glossary[1].Type = &lit
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{sense, "(v1) [to eat; essen (ger, lit)]"},
+		{testSense("", "to eat"), "[to eat]"},
+		{[]JmdictSource{{Content: "Brot", Language: &ger, Wasei: "y"}}, "[Brot (ger, y)]"},
+	}
+
+	for _, test := range tests {
+		if got := describeDiffValue(test.value); got != test.want {
+			t.Errorf("describeDiffValue(%+v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestDiffJmdictIgnoresImpliedFlags(t *testing.T) {
+	eng := "eng"
+	before := testEntry(1, "", "たべる", testSense("v1", "to eat"))
+	after := testEntry(1, "", "たべる", testSense("v1", "to eat"))
+	before.Sense[0].Glossary[0].Language = &eng
+	after.Sense[0].Glossary[0].Language = &eng
+	after.Sense[0].Glossary[0].LanguageImplied = true
+
+	if changes := diffJmdictEntry(&before, &after); len(changes) != 0 {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+}
@@ -0,0 +1,394 @@
+package jmdict
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type DTD struct {
+	// The internal DTD subset the declarations were parsed from.
+	Source string
+
+	// General and parameter entity declarations, in order of declaration.
+	Entities []DTDEntity
+
+	// Element declarations, in order of declaration.
+	Elements []DTDElement
+
+	// Attribute declarations. A single ATTLIST declaring several
+	// attributes results in one value per attribute.
+	Attributes []DTDAttribute
+}
+
+type DTDEntity struct {
+	Name string
+
+	// The replacement text of internal entities.
+	Value string
+
+	// The system identifier of external entities.
+	System string
+
+	// Set for parameter entities (declared with "%"), which are only
+	// referenced from within the DTD itself.
+	Parameter bool
+}
+
+type DTDElement struct {
+	Name string
+
+	// The content specification, e.g. "(keb, ke_inf*, ke_pri*)",
+	// "(#PCDATA)", "EMPTY" or "ANY".
+	Content string
+}
+
+type DTDAttribute struct {
+	// The name of the element the attribute belongs to.
+	Element string
+
+	// The qualified name of the attribute, e.g. "xml:lang".
+	Name string
+
+	// The attribute type, e.g. "CDATA", "ID" or an enumeration such as
+	// "(part|full)".
+	Type string
+
+	// One of "#REQUIRED", "#IMPLIED", "#FIXED", or empty when the
+	// declaration only provides a default value.
+	Mode string
+
+	// The default value of the attribute when it is absent from an
+	// element, if one is declared.
+	Default    string
+	HasDefault bool
+}
+
+// ParseDTD parses the declarations of an internal DTD subset, i.e. the
+// text between the brackets of a DOCTYPE declaration.
+func ParseDTD(subset string) (*DTD, error) {
+	p := dtdParser{input: subset}
+	dtd := &DTD{Source: subset}
+
+	for {
+		p.skipSpace()
+		if p.done() {
+			return dtd, nil
+		}
+
+		switch {
+		case p.consume("<!--"):
+			if !p.skipPast("-->") {
+				return nil, p.errorf("unterminated comment")
+			}
+		case p.consume("<?"):
+			if !p.skipPast("?>") {
+				return nil, p.errorf("unterminated processing instruction")
+			}
+		case p.consume("<!ENTITY"):
+			entity, err := p.parseEntity()
+			if err != nil {
+				return nil, err
+			}
+			dtd.Entities = append(dtd.Entities, entity)
+		case p.consume("<!ELEMENT"):
+			element, err := p.parseElement()
+			if err != nil {
+				return nil, err
+			}
+			dtd.Elements = append(dtd.Elements, element)
+		case p.consume("<!ATTLIST"):
+			attributes, err := p.parseAttributeList()
+			if err != nil {
+				return nil, err
+			}
+			dtd.Attributes = append(dtd.Attributes, attributes...)
+		case p.consume("<!"):
+			// NOTATION and conditional sections are not used by the
+			// EDRDG files; skip them.
+			if !p.skipPast(">") {
+				return nil, p.errorf("unterminated declaration")
+			}
+		case p.consume("%"):
+			// Parameter entity references are not expanded.
+			if !p.skipPast(";") {
+				return nil, p.errorf("unterminated parameter entity reference")
+			}
+		default:
+			return nil, p.errorf("unexpected character %q", p.input[p.pos])
+		}
+	}
+}
+
+// EntityMap returns the replacement text of all internal general entities,
+// keyed by entity name.
+func (d *DTD) EntityMap() map[string]string {
+	entities := make(map[string]string)
+	for _, entity := range d.Entities {
+		if !entity.Parameter && entity.System == "" {
+			entities[entity.Name] = entity.Value
+		}
+	}
+
+	return entities
+}
+
+func (d *DTD) Element(name string) (DTDElement, bool) {
+	for _, element := range d.Elements {
+		if element.Name == name {
+			return element, true
+		}
+	}
+
+	return DTDElement{}, false
+}
+
+func (d *DTD) Attribute(element, name string) (DTDAttribute, bool) {
+	for _, attribute := range d.Attributes {
+		if attribute.Element == element && attribute.Name == name {
+			return attribute, true
+		}
+	}
+
+	return DTDAttribute{}, false
+}
+
+// AttributeDefault returns the value implied for an attribute when it is
+// omitted, e.g. "eng" for the xml:lang attribute of gloss.
+func (d *DTD) AttributeDefault(element, name string) (string, bool) {
+	if attribute, ok := d.Attribute(element, name); ok && attribute.HasDefault {
+		return attribute.Default, true
+	}
+
+	return "", false
+}
+
+type dtdParser struct {
+	input string
+	pos   int
+}
+
+func (p *dtdParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.input[:p.pos], "\n") + 1
+	return fmt.Errorf("dtd line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *dtdParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *dtdParser) skipSpace() {
+	for !p.done() && isDTDSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *dtdParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.input[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+
+	return false
+}
+
+func (p *dtdParser) skipPast(terminator string) bool {
+	i := strings.Index(p.input[p.pos:], terminator)
+	if i < 0 {
+		p.pos = len(p.input)
+		return false
+	}
+
+	p.pos += i + len(terminator)
+	return true
+}
+
+func (p *dtdParser) name() (string, error) {
+	p.skipSpace()
+
+	start := p.pos
+	for !p.done() {
+		c := rune(p.input[p.pos])
+		if isDTDSpace(p.input[p.pos]) || strings.ContainsRune(">\"'()|,", c) {
+			break
+		}
+		p.pos++
+	}
+
+	if start == p.pos {
+		return "", p.errorf("expected name")
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *dtdParser) quoted() (string, error) {
+	p.skipSpace()
+	if p.done() || (p.input[p.pos] != '"' && p.input[p.pos] != '\'') {
+		return "", p.errorf("expected quoted string")
+	}
+
+	quote := p.input[p.pos]
+	end := strings.IndexByte(p.input[p.pos+1:], quote)
+	if end < 0 {
+		return "", p.errorf("unterminated quoted string")
+	}
+
+	value := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+// group reads a parenthesized expression, such as a content model or an
+// enumeration, including any trailing occurrence indicator.
+func (p *dtdParser) group() (string, error) {
+	p.skipSpace()
+
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		switch p.input[p.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		p.pos++
+
+		if depth == 0 {
+			if !p.done() && strings.ContainsRune("*+?", rune(p.input[p.pos])) {
+				p.pos++
+			}
+			return p.input[start:p.pos], nil
+		}
+	}
+
+	return "", p.errorf("unbalanced parentheses")
+}
+
+func (p *dtdParser) end() error {
+	p.skipSpace()
+	if !p.consume(">") {
+		return p.errorf("expected '>'")
+	}
+
+	return nil
+}
+
+func (p *dtdParser) parseEntity() (DTDEntity, error) {
+	var entity DTDEntity
+
+	p.skipSpace()
+	if p.consume("%") {
+		entity.Parameter = true
+	}
+
+	var err error
+	if entity.Name, err = p.name(); err != nil {
+		return entity, err
+	}
+
+	p.skipSpace()
+	switch {
+	case p.consume("SYSTEM"):
+		if entity.System, err = p.quoted(); err != nil {
+			return entity, err
+		}
+	case p.consume("PUBLIC"):
+		if _, err = p.quoted(); err != nil {
+			return entity, err
+		}
+		if entity.System, err = p.quoted(); err != nil {
+			return entity, err
+		}
+	default:
+		if entity.Value, err = p.quoted(); err != nil {
+			return entity, err
+		}
+	}
+
+	// Unparsed entities carry a notation name, which is not retained.
+	p.skipSpace()
+	if p.consume("NDATA") {
+		if _, err = p.name(); err != nil {
+			return entity, err
+		}
+	}
+
+	return entity, p.end()
+}
+
+func (p *dtdParser) parseElement() (DTDElement, error) {
+	var element DTDElement
+
+	var err error
+	if element.Name, err = p.name(); err != nil {
+		return element, err
+	}
+
+	p.skipSpace()
+	if !p.done() && p.input[p.pos] == '(' {
+		element.Content, err = p.group()
+	} else {
+		element.Content, err = p.name()
+	}
+	if err != nil {
+		return element, err
+	}
+
+	return element, p.end()
+}
+
+func (p *dtdParser) parseAttributeList() ([]DTDAttribute, error) {
+	element, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes []DTDAttribute
+	for {
+		p.skipSpace()
+		if p.consume(">") {
+			return attributes, nil
+		}
+
+		attribute := DTDAttribute{Element: element}
+		if attribute.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if !p.done() && p.input[p.pos] == '(' {
+			attribute.Type, err = p.group()
+		} else if attribute.Type, err = p.name(); err == nil && attribute.Type == "NOTATION" {
+			var notations string
+			notations, err = p.group()
+			attribute.Type += " " + notations
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		switch {
+		case p.consume("#REQUIRED"):
+			attribute.Mode = "#REQUIRED"
+		case p.consume("#IMPLIED"):
+			attribute.Mode = "#IMPLIED"
+		case p.consume("#FIXED"):
+			attribute.Mode = "#FIXED"
+			fallthrough
+		default:
+			if attribute.Default, err = p.quoted(); err != nil {
+				return nil, err
+			}
+			attribute.HasDefault = true
+		}
+
+		attributes = append(attributes, attribute)
+	}
+}
+
+func isDTDSpace(c byte) bool {
+	return c < 0x80 && unicode.IsSpace(rune(c))
+}
@@ -0,0 +1,126 @@
+package jmdict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDTDAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		subset  string
+		element string
+		attr    string
+		want    DTDAttribute
+	}{
+		{
+			name:    "double-quoted default",
+			subset:  `<!ATTLIST gloss xml:lang CDATA "eng">`,
+			element: "gloss",
+			attr:    "xml:lang",
+			want:    DTDAttribute{Element: "gloss", Name: "xml:lang", Type: "CDATA", Default: "eng", HasDefault: true},
+		},
+		{
+			name:    "single-quoted default",
+			subset:  `<!ATTLIST lsource ls_type CDATA 'full'>`,
+			element: "lsource",
+			attr:    "ls_type",
+			want:    DTDAttribute{Element: "lsource", Name: "ls_type", Type: "CDATA", Default: "full", HasDefault: true},
+		},
+		{
+			name:    "fixed",
+			subset:  `<!ATTLIST entry version CDATA #FIXED "1.09">`,
+			element: "entry",
+			attr:    "version",
+			want:    DTDAttribute{Element: "entry", Name: "version", Type: "CDATA", Mode: "#FIXED", Default: "1.09", HasDefault: true},
+		},
+		{
+			name:    "implied",
+			subset:  `<!ATTLIST gloss g_type CDATA #IMPLIED>`,
+			element: "gloss",
+			attr:    "g_type",
+			want:    DTDAttribute{Element: "gloss", Name: "g_type", Type: "CDATA", Mode: "#IMPLIED"},
+		},
+		{
+			name:    "enumeration",
+			subset:  `<!ATTLIST lsource ls_type (part | full) "full">`,
+			element: "lsource",
+			attr:    "ls_type",
+			want:    DTDAttribute{Element: "lsource", Name: "ls_type", Type: "(part | full)", Default: "full", HasDefault: true},
+		},
+		{
+			name:    "several attributes",
+			subset:  "<!ATTLIST gloss\n\txml:lang CDATA \"eng\"\n\tg_gend CDATA #IMPLIED\n>",
+			element: "gloss",
+			attr:    "g_gend",
+			want:    DTDAttribute{Element: "gloss", Name: "g_gend", Type: "CDATA", Mode: "#IMPLIED"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dtd, err := ParseDTD(test.subset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := dtd.Attribute(test.element, test.attr)
+			if !ok {
+				t.Fatalf("attribute %s of %s not found in %+v", test.attr, test.element, dtd.Attributes)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseDTDEntities(t *testing.T) {
+	subset := `
+<!-- parts of speech -->
+<!ENTITY v1 "Ichidan verb">
+<!ENTITY adj-i 'adjective (keiyoushi)'>
+<!ENTITY % common "CDATA">
+<!ENTITY logo SYSTEM "logo.gif">
+<!ELEMENT entry (ent_seq, k_ele*, r_ele+, sense+)>
+<?processing instruction?>
+<!NOTATION gif SYSTEM "image/gif">
+`
+
+	dtd, err := ParseDTD(subset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"v1":    "Ichidan verb",
+		"adj-i": "adjective (keiyoushi)",
+	}
+	if got := dtd.EntityMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EntityMap() = %v, want %v", got, want)
+	}
+
+	if element, ok := dtd.Element("entry"); !ok || element.Content != "(ent_seq, k_ele*, r_ele+, sense+)" {
+		t.Errorf("Element(entry) = %+v, %v", element, ok)
+	}
+}
+
+func TestParseDTDErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		subset string
+	}{
+		{"unterminated entity value", `<!ENTITY v1 "Ichidan verb>`},
+		{"missing attribute default", `<!ATTLIST gloss xml:lang CDATA>`},
+		{"unbalanced enumeration", `<!ATTLIST lsource ls_type (part|full "full">`},
+		{"unterminated comment", `<!-- parts of speech`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseDTD(test.subset); err == nil {
+				t.Errorf("ParseDTD(%q) succeeded", test.subset)
+			}
+		})
+	}
+}
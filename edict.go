@@ -0,0 +1,403 @@
+package jmdict
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteEdict2 writes the English glosses of the dictionary in the UTF-8
+// EDICT2 format, one line per entry:
+//
+//	KANJI-1;KANJI-2 [KANA-1;KANA-2] /(general information) (1) gloss/gloss/(2) gloss/EntLnnnnnnn/
+//
+// Tags are written as loaded, so the dictionary should be loaded without
+// transform to produce the usual entity codes (e.g. "v1"). Entries without
+// any English gloss are omitted.
+func WriteEdict2(writer io.Writer, dict Jmdict) error {
+	w := bufio.NewWriter(writer)
+	for i := range dict.Entries {
+		if line, ok := formatEdict2(&dict.Entries[i]); ok {
+			if _, err := w.WriteString(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+func formatEdict2(entry *JmdictEntry) (string, bool) {
+	senses := countEdictSenses(entry)
+	if senses == 0 {
+		return "", false
+	}
+
+	common := stringSet(commonPriorities)
+	isCommon := false
+
+	var kanji []string
+	for _, k := range entry.Kanji {
+		text := k.Expression
+		for _, info := range k.Information {
+			text += "(" + info + ")"
+		}
+		if containsAny(k.Priorities, common) {
+			text += "(P)"
+			isCommon = true
+		}
+		kanji = append(kanji, text)
+	}
+
+	var readings []string
+	for _, r := range entry.Readings {
+		text := r.Reading
+		if len(r.Restrictions) > 0 {
+			text += "(" + strings.Join(r.Restrictions, ";") + ")"
+		}
+		for _, info := range r.Information {
+			text += "(" + info + ")"
+		}
+		if containsAny(r.Priorities, common) {
+			text += "(P)"
+			isCommon = true
+		}
+		readings = append(readings, text)
+	}
+
+	var b strings.Builder
+	if len(kanji) > 0 {
+		fmt.Fprintf(&b, "%s [%s] /", strings.Join(kanji, ";"), strings.Join(readings, ";"))
+	} else {
+		fmt.Fprintf(&b, "%s /", strings.Join(readings, ";"))
+	}
+
+	number := 0
+	for i := range entry.Sense {
+		sense := &entry.Sense[i]
+
+		glosses := edictGlosses(sense)
+		if len(glosses) == 0 {
+			continue
+		}
+		number++
+
+		// Sense numbers are only used when there is more than one sense,
+		// and follow the part-of-speech tags.
+		var parts []string
+		if len(sense.PartsOfSpeech) > 0 {
+			parts = append(parts, "("+strings.Join(sense.PartsOfSpeech, ",")+")")
+		}
+		if senses > 1 {
+			parts = append(parts, fmt.Sprintf("(%d)", number))
+		}
+		for _, field := range sense.Fields {
+			parts = append(parts, "{"+field+"}")
+		}
+		if len(sense.Misc) > 0 {
+			parts = append(parts, "("+strings.Join(sense.Misc, ",")+")")
+		}
+		for _, dialect := range sense.Dialects {
+			parts = append(parts, "("+dialect+":)")
+		}
+		for _, info := range sense.Information {
+			parts = append(parts, "("+info+")")
+		}
+		if len(sense.References) > 0 {
+			parts = append(parts, "(See "+strings.Join(sense.References, ",")+")")
+		}
+		if len(sense.Antonyms) > 0 {
+			parts = append(parts, "(ant: "+strings.Join(sense.Antonyms, ",")+")")
+		}
+
+		b.WriteString(strings.Join(append(parts, glosses[0]), " "))
+		b.WriteString("/")
+		for _, gloss := range glosses[1:] {
+			b.WriteString(gloss)
+			b.WriteString("/")
+		}
+	}
+
+	if isCommon {
+		b.WriteString("(P)/")
+	}
+
+	fmt.Fprintf(&b, "EntL%d/\n", entry.Sequence)
+	return b.String(), true
+}
+
+// edictGlosses returns the English glosses of a sense. Slashes delimit
+// glosses in EDICT, so any within a gloss are replaced.
+func edictGlosses(sense *JmdictSense) []string {
+	var glosses []string
+	for _, gloss := range sense.Glossary {
+		if gloss.Language == nil || *gloss.Language == "eng" {
+			glosses = append(glosses, strings.ReplaceAll(gloss.Content, "/", "／"))
+		}
+	}
+
+	return glosses
+}
+
+func countEdictSenses(entry *JmdictEntry) int {
+	var count int
+	for i := range entry.Sense {
+		if len(edictGlosses(&entry.Sense[i])) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// edictCommonPriority is the priority given to elements marked (P) when
+// reading EDICT2, which does not tell which list marked them as common.
+const edictCommonPriority = "ichi1"
+
+// LoadEdict2 reads a dictionary in the EDICT2 format written by WriteEdict2.
+// EDICT2 holds less than JMdict: the parenthesized tags of a sense are read
+// as parts of speech before the sense number and as misc tags after it,
+// glosses are English, and (P) markers become the ichi1 priority. Tags are
+// kept as entity codes, as when loading JMdict without transform. The
+// header line, if any, provides the creation date.
+func LoadEdict2(reader io.Reader, options ...LoadOption) (Jmdict, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var dict Jmdict
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "　？？？") {
+			if match := createdPattern.FindStringSubmatch(strings.ToLower(text)); match != nil {
+				dict.Info.Created = match[1]
+			}
+			continue
+		}
+
+		entry, err := parseEdict2(text)
+		if err != nil {
+			return dict, fmt.Errorf("edict2 line %d: %w", line, err)
+		}
+
+		dict.Entries = append(dict.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return dict, err
+	}
+
+	return ApplyJmdictOptions(dict, options...), nil
+}
+
+func parseEdict2(line string) (JmdictEntry, error) {
+	var entry JmdictEntry
+
+	head, body, ok := strings.Cut(line, " /")
+	if !ok {
+		return entry, errors.New("missing glosses")
+	}
+
+	readings := head
+	if i := strings.Index(head, " ["); i >= 0 && strings.HasSuffix(head, "]") {
+		readings = head[i+2 : len(head)-1]
+
+		for _, item := range splitEdictList(head[:i]) {
+			text, markers := splitEdictMarkers(item)
+			kanji := JmdictKanji{Expression: text}
+			for _, marker := range markers {
+				if marker == "P" {
+					kanji.Priorities = append(kanji.Priorities, edictCommonPriority)
+				} else {
+					kanji.Information = append(kanji.Information, marker)
+				}
+			}
+			entry.Kanji = append(entry.Kanji, kanji)
+		}
+	}
+
+	for _, item := range splitEdictList(readings) {
+		text, markers := splitEdictMarkers(item)
+		reading := JmdictReading{Reading: text}
+		for _, marker := range markers {
+			switch {
+			case marker == "P":
+				reading.Priorities = append(reading.Priorities, edictCommonPriority)
+			case isEdictCode(marker):
+				reading.Information = append(reading.Information, marker)
+			default:
+				reading.Restrictions = append(reading.Restrictions, strings.Split(marker, ";")...)
+			}
+		}
+		entry.Readings = append(entry.Readings, reading)
+	}
+
+	if len(entry.Readings) == 0 || entry.Readings[0].Reading == "" {
+		return entry, errors.New("missing reading")
+	}
+
+	for _, field := range strings.Split(strings.TrimSuffix(body, "/"), "/") {
+		switch {
+		case field == "" || field == "(P)":
+			continue
+		case strings.HasPrefix(field, "EntL"):
+			sequence, err := strconv.Atoi(strings.TrimSuffix(field[4:], "X"))
+			if err != nil {
+				return entry, fmt.Errorf("invalid entry number %q", field)
+			}
+			entry.Sequence = sequence
+			continue
+		}
+
+		tags, gloss := splitEdictTags(field)
+		if n := len(entry.Sense); n > 0 && !hasEdictSenseNumber(tags) {
+			// Only the first gloss of a sense carries tags.
+			entry.Sense[n-1].Glossary = append(entry.Sense[n-1].Glossary, JmdictGlossary{Content: field})
+			continue
+		}
+
+		var sense JmdictSense
+		numbered, coded := false, false
+		for _, tag := range tags {
+			text := tag[1 : len(tag)-1]
+			if tag[0] == '{' {
+				sense.Fields = append(sense.Fields, text)
+				continue
+			}
+
+			switch {
+			case isEdictSenseNumber(tag):
+				numbered = true
+			case strings.HasPrefix(text, "See "):
+				sense.References = append(sense.References, strings.Split(text[4:], ",")...)
+			case strings.HasPrefix(text, "ant: "):
+				sense.Antonyms = append(sense.Antonyms, strings.Split(text[5:], ",")...)
+			case strings.HasSuffix(text, ":") && isEdictCode(strings.TrimSuffix(text, ":")):
+				sense.Dialects = append(sense.Dialects, strings.TrimSuffix(text, ":"))
+			case isEdictCode(text) && !numbered && !coded:
+				sense.PartsOfSpeech = strings.Split(text, ",")
+				coded = true
+			case isEdictCode(text):
+				sense.Misc = append(sense.Misc, strings.Split(text, ",")...)
+			default:
+				sense.Information = append(sense.Information, text)
+			}
+		}
+
+		sense.Glossary = append(sense.Glossary, JmdictGlossary{Content: gloss})
+		entry.Sense = append(entry.Sense, sense)
+	}
+
+	return entry, nil
+}
+
+// splitEdictList splits a list of kanji or reading elements, which are
+// separated by semicolons outside of the parenthesized markers.
+func splitEdictList(text string) []string {
+	var (
+		items []string
+		depth int
+		start int
+	)
+
+	for i, c := range text {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ';':
+			if depth == 0 {
+				items = append(items, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(items, text[start:])
+}
+
+// splitEdictMarkers splits the parenthesized markers from the end of a
+// kanji or reading element, such as (P) or (ik).
+func splitEdictMarkers(item string) (string, []string) {
+	var markers []string
+	for strings.HasSuffix(item, ")") {
+		i := strings.LastIndex(item, "(")
+		if i <= 0 {
+			break
+		}
+
+		markers = append([]string{item[i+1 : len(item)-1]}, markers...)
+		item = item[:i]
+	}
+
+	return item, markers
+}
+
+// splitEdictTags splits the space separated tags in parentheses or braces
+// from the start of a gloss.
+func splitEdictTags(field string) ([]string, string) {
+	var tags []string
+	for len(field) > 0 && (field[0] == '(' || field[0] == '{') {
+		closing := ")"
+		if field[0] == '{' {
+			closing = "}"
+		}
+
+		end := strings.Index(field, closing+" ")
+		if end < 0 {
+			break
+		}
+
+		tags = append(tags, field[:end+1])
+		field = field[end+2:]
+	}
+
+	return tags, field
+}
+
+func hasEdictSenseNumber(tags []string) bool {
+	for _, tag := range tags {
+		if isEdictSenseNumber(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEdictSenseNumber(tag string) bool {
+	_, err := strconv.Atoi(strings.Trim(tag, "()"))
+	return err == nil && strings.HasPrefix(tag, "(")
+}
+
+// isEdictCode reports whether the text is a comma separated list of
+// entity codes (such as "v5k-s,vt"), as opposed to free text.
+func isEdictCode(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	for _, code := range strings.Split(text, ",") {
+		if code == "" {
+			return false
+		}
+		for _, c := range code {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
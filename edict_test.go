@@ -0,0 +1,101 @@
+package jmdict
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteEdict2(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEdict2(&buf, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "食べる(P) [たべる(P)] /(v1) to eat/(P)/EntL1358280/\n" +
+		"学校 [がっこう] /(n) school/EntL1206730/\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestLoadEdict2(t *testing.T) {
+	const edict2 = "　？？？ /,/ [？？？] /EDICT2 Japanese-English Electronic Dictionary Files/Created: 2022-07-14/\n" +
+		"買う(P);購う(oK) [かう(P)] /(v5u,vt) (1) to buy/to purchase/(2) (ksb:) (arch) to value/(P)/EntL1311290X/\n"
+
+	dict, err := LoadEdict2(strings.NewReader(edict2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dict.Info.Created != "2022-07-14" {
+		t.Errorf("Created = %q", dict.Info.Created)
+	}
+
+	if len(dict.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(dict.Entries))
+	}
+
+	entry := dict.Entries[0]
+	if entry.Sequence != 1311290 {
+		t.Errorf("Sequence = %d", entry.Sequence)
+	}
+
+	if !reflect.DeepEqual(entry.Kanji[0].Priorities, []string{"ichi1"}) || !reflect.DeepEqual(entry.Kanji[1].Information, []string{"oK"}) {
+		t.Errorf("Kanji = %+v", entry.Kanji)
+	}
+
+	if len(entry.Sense) != 2 {
+		t.Fatalf("got %d senses, want 2", len(entry.Sense))
+	}
+
+	first, second := entry.Sense[0], entry.Sense[1]
+	if !reflect.DeepEqual(first.PartsOfSpeech, []string{"v5u", "vt"}) || len(first.Glossary) != 2 || first.Glossary[1].Content != "to purchase" {
+		t.Errorf("Sense[0] = %+v", first)
+	}
+
+	if !reflect.DeepEqual(second.Dialects, []string{"ksb"}) || !reflect.DeepEqual(second.Misc, []string{"arch"}) || second.Glossary[0].Content != "to value" {
+		t.Errorf("Sense[1] = %+v", second)
+	}
+}
+
+func TestEdict2RoundTrip(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML), WithLanguages("eng"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEdict2(&buf, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadEdict2(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Entries) != len(dict.Entries) {
+		t.Fatalf("got %d entries, want %d", len(loaded.Entries), len(dict.Entries))
+	}
+
+	for i, entry := range loaded.Entries {
+		if got, want := testEntryXML(t, &entry), testEntryXML(t, &dict.Entries[i]); got != want {
+			t.Errorf("entry %d:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
+
+func TestLoadEdict2Errors(t *testing.T) {
+	for _, line := range []string{"買う [かう]", "買う [かう] /(v5u) to buy/EntLx/"} {
+		if _, err := LoadEdict2(strings.NewReader(line)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("LoadEdict2(%q) error = %v", line, err)
+		}
+	}
+}
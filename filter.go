@@ -0,0 +1,239 @@
+package jmdict
+
+// Predicate reports whether a dictionary entry or kanji character should be
+// included in a subset. Predicates can be passed to the loaders through
+// WithJmdictFilter, WithJmnedictFilter and WithKanjidicFilter, or applied to
+// a loaded dictionary with FilterJmdict, FilterJmnedict and FilterKanjidic.
+//
+// Predicates matching coded information (parts of speech, fields, name
+// types, etc.) compare against the values as loaded: entity codes such as
+// "v1" for dictionaries loaded without transform, or their expanded
+// descriptions otherwise.
+type Predicate[T any] func(*T) bool
+
+func And[T any](predicates ...Predicate[T]) Predicate[T] {
+	return func(value *T) bool {
+		for _, predicate := range predicates {
+			if !predicate(value) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func Or[T any](predicates ...Predicate[T]) Predicate[T] {
+	return func(value *T) bool {
+		for _, predicate := range predicates {
+			if predicate(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func Not[T any](predicate Predicate[T]) Predicate[T] {
+	return func(value *T) bool {
+		return !predicate(value)
+	}
+}
+
+func FilterJmdict(dict Jmdict, predicate Predicate[JmdictEntry]) Jmdict {
+	result := Jmdict{Info: dict.Info}
+	for i := range dict.Entries {
+		if predicate(&dict.Entries[i]) {
+			result.Entries = append(result.Entries, dict.Entries[i])
+		}
+	}
+
+	return result
+}
+
+func FilterJmnedict(dic Jmnedict, predicate Predicate[JmnedictEntry]) Jmnedict {
+	result := Jmnedict{Info: dic.Info}
+	for i := range dic.Entries {
+		if predicate(&dic.Entries[i]) {
+			result.Entries = append(result.Entries, dic.Entries[i])
+		}
+	}
+
+	return result
+}
+
+func FilterKanjidic(dic Kanjidic, predicate Predicate[KanjidicCharacter]) Kanjidic {
+	result := Kanjidic{Header: dic.Header, Info: dic.Info}
+	for i := range dic.Characters {
+		if predicate(&dic.Characters[i]) {
+			result.Characters = append(result.Characters, dic.Characters[i])
+		}
+	}
+
+	return result
+}
+
+// The priority codes which mark an entry as common, corresponding to the
+// "(P)" marker in the EDICT files.
+var commonPriorities = []string{"news1", "ichi1", "spec1", "spec2", "gai1"}
+
+func containsAny(values []string, set map[string]bool) bool {
+	for _, value := range values {
+		if set[value] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
+
+// JmdictCommon matches entries with at least one kanji or reading element
+// carrying one of the priority codes news1, ichi1, spec1, spec2 or gai1.
+func JmdictCommon() Predicate[JmdictEntry] {
+	return JmdictPriority(commonPriorities...)
+}
+
+// JmdictPriority matches entries with a kanji or reading element carrying
+// any of the given priority codes (e.g. "news1", "nf05").
+func JmdictPriority(codes ...string) Predicate[JmdictEntry] {
+	set := stringSet(codes)
+	return func(entry *JmdictEntry) bool {
+		for _, kanji := range entry.Kanji {
+			if containsAny(kanji.Priorities, set) {
+				return true
+			}
+		}
+
+		for _, reading := range entry.Readings {
+			if containsAny(reading.Priorities, set) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func jmdictSenseTags(tags []string, field func(*JmdictSense) []string) Predicate[JmdictEntry] {
+	set := stringSet(tags)
+	return func(entry *JmdictEntry) bool {
+		for i := range entry.Sense {
+			if containsAny(field(&entry.Sense[i]), set) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// JmdictPartOfSpeech matches entries with a sense tagged with any of the
+// given parts of speech.
+func JmdictPartOfSpeech(tags ...string) Predicate[JmdictEntry] {
+	return jmdictSenseTags(tags, func(sense *JmdictSense) []string { return sense.PartsOfSpeech })
+}
+
+func JmdictField(tags ...string) Predicate[JmdictEntry] {
+	return jmdictSenseTags(tags, func(sense *JmdictSense) []string { return sense.Fields })
+}
+
+func JmdictMisc(tags ...string) Predicate[JmdictEntry] {
+	return jmdictSenseTags(tags, func(sense *JmdictSense) []string { return sense.Misc })
+}
+
+func JmdictDialect(tags ...string) Predicate[JmdictEntry] {
+	return jmdictSenseTags(tags, func(sense *JmdictSense) []string { return sense.Dialects })
+}
+
+// JmdictGlossLanguage matches entries with at least one gloss in any of the
+// given ISO 639-2 languages. Glosses without a language are English.
+func JmdictGlossLanguage(languages ...string) Predicate[JmdictEntry] {
+	set := stringSet(languages)
+	return func(entry *JmdictEntry) bool {
+		for _, sense := range entry.Sense {
+			for _, gloss := range sense.Glossary {
+				if set[languageOf(gloss.Language, "eng")] {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+func JmnedictNameType(types ...string) Predicate[JmnedictEntry] {
+	set := stringSet(types)
+	return func(entry *JmnedictEntry) bool {
+		for _, trans := range entry.Translations {
+			if containsAny(trans.NameTypes, set) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func JmnedictTranslationLanguage(languages ...string) Predicate[JmnedictEntry] {
+	set := stringSet(languages)
+	return func(entry *JmnedictEntry) bool {
+		for _, trans := range entry.Translations {
+			if set[languageOf(trans.Language, "eng")] {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// KanjidicGrade matches characters taught in any of the given grades.
+func KanjidicGrade(grades ...int) Predicate[KanjidicCharacter] {
+	return func(character *KanjidicCharacter) bool {
+		if grade, ok, err := character.Grade(); ok && err == nil {
+			for _, g := range grades {
+				if grade == g {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// KanjidicJlpt matches characters at any of the given (former, four level)
+// JLPT levels.
+func KanjidicJlpt(levels ...int) Predicate[KanjidicCharacter] {
+	return func(character *KanjidicCharacter) bool {
+		if level, ok, err := character.JLPT(); ok && err == nil {
+			for _, l := range levels {
+				if level == l {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// KanjidicStrokeCount matches characters whose accepted stroke count is
+// within the inclusive range.
+func KanjidicStrokeCount(min, max int) Predicate[KanjidicCharacter] {
+	return func(character *KanjidicCharacter) bool {
+		count, err := character.StrokeCount()
+		return err == nil && count >= min && count <= max
+	}
+}
@@ -0,0 +1,129 @@
+package jmdict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJmdictPredicates(t *testing.T) {
+	common := testEntry(1, "食べる", "たべる", testSense("v1,vt", "to eat"))
+	common.Kanji[0].Priorities = []string{"ichi1", "nf05"}
+
+	rare := testEntry(2, "", "ぴえん", testSense("int", "boo hoo"))
+	rare.Sense[0].Misc = []string{"sl"}
+	rare.Sense[0].Fields = []string{"internet"}
+	rare.Sense[0].Glossary[0].Language = impliedValue("eng")
+
+	dialect := testEntry(3, "", "おおきに", testSense("int", "danke"))
+	dialect.Sense[0].Dialects = []string{"ksb"}
+	dialect.Sense[0].Glossary[0].Language = impliedValue("ger")
+
+	entries := []JmdictEntry{common, rare, dialect}
+
+	tests := []struct {
+		name      string
+		predicate Predicate[JmdictEntry]
+		want      []int
+	}{
+		{"common", JmdictCommon(), []int{1}},
+		{"priority", JmdictPriority("nf05", "news2"), []int{1}},
+		{"part of speech", JmdictPartOfSpeech("int"), []int{2, 3}},
+		{"field", JmdictField("internet"), []int{2}},
+		{"misc", JmdictMisc("sl", "arch"), []int{2}},
+		{"dialect", JmdictDialect("ksb"), []int{3}},
+		{"gloss language", JmdictGlossLanguage("eng"), []int{1, 2}},
+		{"and", And(JmdictPartOfSpeech("int"), JmdictGlossLanguage("ger")), []int{3}},
+		{"or", Or(JmdictCommon(), JmdictDialect("ksb")), []int{1, 3}},
+		{"not", Not(JmdictCommon()), []int{2, 3}},
+		{"empty and", And[JmdictEntry](), []int{1, 2, 3}},
+		{"empty or", Or[JmdictEntry](), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []int
+			for _, entry := range FilterJmdict(Jmdict{Entries: entries}, test.predicate).Entries {
+				got = append(got, entry.Sequence)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKanjidicPredicates(t *testing.T) {
+	grade, jlpt := "2", "4"
+	dic := Kanjidic{Characters: []KanjidicCharacter{
+		{Literal: "食", Misc: KanjidicMisc{Grade: &grade, JlptLevel: &jlpt, StrokeCounts: []string{"9"}}},
+		{Literal: "喰", Misc: KanjidicMisc{StrokeCounts: []string{"12"}}},
+	}}
+
+	tests := []struct {
+		name      string
+		predicate Predicate[KanjidicCharacter]
+		want      string
+	}{
+		{"grade", KanjidicGrade(1, 2), "食"},
+		{"jlpt", KanjidicJlpt(4), "食"},
+		{"stroke count", KanjidicStrokeCount(10, 15), "喰"},
+		{"stroke count bounds", KanjidicStrokeCount(9, 12), "食喰"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			for _, character := range FilterKanjidic(dic, test.predicate).Characters {
+				got += character.Literal
+			}
+
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithJmdictFilter(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML),
+		WithJmdictFilter(JmdictPartOfSpeech("v1", "n")),
+		WithJmdictFilter(JmdictCommon()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dict.Entries) != 1 || dict.Entries[0].Sequence != 1358280 {
+		t.Errorf("entries = %+v, want 1358280 alone", dict.Entries)
+	}
+}
+
+func TestWithJmdictFilterReuse(t *testing.T) {
+	common := WithJmdictFilter(JmdictCommon())
+	nouns := WithJmdictFilter(JmdictPartOfSpeech("n"))
+
+	// Reusing an option must not carry over the filters of earlier loads.
+	for i, test := range []struct {
+		options []LoadOption
+		want    []int
+	}{
+		{[]LoadOption{common}, []int{1358280}},
+		{[]LoadOption{nouns}, []int{1206730}},
+		{[]LoadOption{common, nouns}, nil},
+		{[]LoadOption{nouns}, []int{1206730}},
+	} {
+		dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML), test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int
+		for _, entry := range dict.Entries {
+			got = append(got, entry.Sequence)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("load %d: entries = %v, want %v", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package jmdict
+
+import "strings"
+
+// FuriganaSegment is a part of an expression with its ruby text. Kana
+// segments have no reading.
+type FuriganaSegment struct {
+	Text    string `json:"text"`
+	Reading string `json:"reading,omitempty"`
+}
+
+// JmdictFurigana is the furigana of a headword of JMdict.
+type JmdictFurigana struct {
+	Sequence   int               `json:"sequence"`
+	Expression string            `json:"expression"`
+	Reading    string            `json:"reading"`
+	Segments   []FuriganaSegment `json:"furigana"`
+}
+
+// Furigana splits the expression into segments and assigns each kanji its
+// part of the reading, using the readings of the characters in KANJIDIC2
+// and the sound changes they undergo in compounds:
+//
+//	食べ物, たべもの → 食[た] べ 物[もの]
+//
+// When no such alignment exists or more than one does, as with words read
+// as a whole (今日 きょう), each run of kanji gets the part of the reading
+// between the kana around it. If even that is ambiguous, the whole
+// expression gets the whole reading.
+func Furigana(kanji *KanjiIndex, expression, reading string) []FuriganaSegment {
+	a := aligner{kanji: kanji}
+	if parts, ok := a.align(expression, reading); ok {
+		segments := make([]FuriganaSegment, 0, len(parts))
+		for _, part := range parts {
+			if part.kana {
+				segments = append(segments, FuriganaSegment{Text: part.text})
+			} else {
+				segments = append(segments, FuriganaSegment{Text: part.text, Reading: part.reading})
+			}
+		}
+		return segments
+	}
+
+	if segments, ok := alignKanaRuns(expression, reading); ok {
+		return segments
+	}
+
+	return []FuriganaSegment{{Text: expression, Reading: reading}}
+}
+
+// AlignJmdictFurigana returns the furigana of every pair of kanji and
+// reading element of the dictionary, skipping readings which do not apply
+// to the kanji element (re_restr) or to any (re_nokanji).
+func AlignJmdictFurigana(dict *Jmdict, kanji *KanjiIndex) []JmdictFurigana {
+	var result []JmdictFurigana
+	for i := range dict.Entries {
+		entry := &dict.Entries[i]
+		for _, k := range entry.Kanji {
+			for j := range entry.Readings {
+				reading := &entry.Readings[j]
+				if !readingApplies(reading, k.Expression) {
+					continue
+				}
+
+				result = append(result, JmdictFurigana{
+					Sequence:   entry.Sequence,
+					Expression: k.Expression,
+					Reading:    reading.Reading,
+					Segments:   Furigana(kanji, k.Expression, reading.Reading),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// FormatFurigana writes the segments in the bracket notation used by Anki,
+// where a space marks the start of the text a reading applies to:
+//
+//	食[た]べ 物[もの]
+func FormatFurigana(segments []FuriganaSegment) string {
+	var b strings.Builder
+	for i, segment := range segments {
+		if segment.Reading == "" {
+			b.WriteString(segment.Text)
+			continue
+		}
+
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(segment.Text + "[" + segment.Reading + "]")
+	}
+
+	return b.String()
+}
+
+// alignKanaRuns splits the expression into runs of kana and of other
+// characters, and matches the kana runs against the reading to find the
+// reading of each of the other runs. The result is false if the kana can
+// be matched in more than one way.
+func alignKanaRuns(expression, reading string) ([]FuriganaSegment, bool) {
+	type run struct {
+		text string
+		kana bool
+	}
+
+	var runs []run
+	for _, c := range expression {
+		kana := isKana(c) && !isKanaCounter(c)
+		if n := len(runs); n > 0 && runs[n-1].kana == kana {
+			runs[n-1].text += string(c)
+		} else {
+			runs = append(runs, run{text: string(c), kana: kana})
+		}
+	}
+
+	read := []rune(reading)
+	norm := []rune(katakanaToHiragana(reading))
+
+	var (
+		solution []FuriganaSegment
+		count    int
+	)
+
+	var match func(i, j int, segments []FuriganaSegment)
+	match = func(i, j int, segments []FuriganaSegment) {
+		if count > 1 {
+			return
+		}
+
+		if i == len(runs) {
+			if j == len(norm) {
+				solution = append([]FuriganaSegment(nil), segments...)
+				count++
+			}
+			return
+		}
+
+		r := runs[i]
+		if r.kana {
+			text := []rune(katakanaToHiragana(r.text))
+			if hasRunePrefix(norm[j:], text) {
+				match(i+1, j+len(text), append(segments, FuriganaSegment{Text: r.text}))
+			}
+			return
+		}
+
+		for end := j + 1; end <= len(norm); end++ {
+			match(i+1, end, append(segments, FuriganaSegment{Text: r.text, Reading: string(read[j:end])}))
+		}
+	}
+
+	match(0, 0, nil)
+	return solution, count == 1
+}
@@ -0,0 +1,67 @@
+package jmdict
+
+import "testing"
+
+func TestFurigana(t *testing.T) {
+	kanji := NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{
+		testCharacter("食", "ショク,ジキ,く.う,た.べる", ""),
+		testCharacter("物", "ブツ,モツ,もの", ""),
+		testCharacter("学", "ガク,まな.ぶ", ""),
+		testCharacter("校", "コウ", ""),
+		testCharacter("今", "コン,キン,いま", ""),
+		testCharacter("日", "ニチ,ジツ,ひ,-び,-か", ""),
+		testCharacter("三", "サン,み,み.つ", ""),
+		testCharacter("月", "ゲツ,ガツ,つき", ""),
+		testCharacter("山", "サン,やま", ""),
+		testCharacter("道", "ドウ,みち", ""),
+		testCharacter("茶", "チャ,サ", ""),
+	}})
+
+	tests := []struct {
+		expression string
+		reading    string
+		want       string
+	}{
+		{"食べ物", "たべもの", "食[た]べ 物[もの]"},
+		{"学校", "がっこう", "学[がっ] 校[こう]"},
+		{"三日月", "みかづき", "三[み] 日[か] 月[づき]"},
+		{"山道", "やまみち", "山[やま] 道[みち]"},
+		{"お茶", "おちゃ", "お 茶[ちゃ]"},
+		{"今日", "きょう", "今日[きょう]"},
+
+		// Characters missing from the index are aligned by the kana
+		// around them.
+		{"取り扱い", "とりあつかい", "取[と]り 扱[あつか]い"},
+
+		// Readings which match no alignment are given to the whole
+		// expression.
+		{"食べ物", "ごはん", "食べ物[ごはん]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression+" "+test.reading, func(t *testing.T) {
+			got := FormatFurigana(Furigana(kanji, test.expression, test.reading))
+			if got != test.want {
+				t.Errorf("Furigana(%s, %s) = %s, want %s", test.expression, test.reading, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatFurigana(t *testing.T) {
+	tests := []struct {
+		segments []FuriganaSegment
+		want     string
+	}{
+		{nil, ""},
+		{[]FuriganaSegment{{Text: "すし"}}, "すし"},
+		{[]FuriganaSegment{{Text: "寿司", Reading: "すし"}}, "寿司[すし]"},
+		{[]FuriganaSegment{{Text: "お"}, {Text: "茶", Reading: "ちゃ"}}, "お 茶[ちゃ]"},
+	}
+
+	for _, test := range tests {
+		if got := FormatFurigana(test.segments); got != test.want {
+			t.Errorf("FormatFurigana(%v) = %q, want %q", test.segments, got, test.want)
+		}
+	}
+}
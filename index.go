@@ -0,0 +1,448 @@
+package jmdict
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// JmdictIndex provides lookups of JMdict entries by headword, reading,
+// gloss and sequence number. An index is read-only once built and is safe
+// for concurrent use; it refers to the entries of the dictionary it was
+// built from, which must not be modified while the index is in use.
+type JmdictIndex struct {
+	dict        *Jmdict
+	expressions map[string][]int
+	readings    map[string][]int
+	glossWords  map[string][]int
+	sequences   map[int]int
+}
+
+func NewJmdictIndex(dict *Jmdict) *JmdictIndex {
+	idx := &JmdictIndex{
+		dict:        dict,
+		expressions: make(map[string][]int),
+		readings:    make(map[string][]int),
+		glossWords:  make(map[string][]int),
+		sequences:   make(map[int]int),
+	}
+
+	for i := range dict.Entries {
+		entry := &dict.Entries[i]
+		idx.sequences[entry.Sequence] = i
+
+		for _, kanji := range entry.Kanji {
+			idx.expressions[kanji.Expression] = appendUnique(idx.expressions[kanji.Expression], i)
+		}
+
+		for _, reading := range entry.Readings {
+			idx.readings[reading.Reading] = appendUnique(idx.readings[reading.Reading], i)
+		}
+
+		for _, sense := range entry.Sense {
+			for _, gloss := range sense.Glossary {
+				for _, word := range glossWords(gloss.Content) {
+					idx.glossWords[word] = appendUnique(idx.glossWords[word], i)
+				}
+			}
+		}
+	}
+
+	return idx
+}
+
+func (idx *JmdictIndex) entries(indices []int) []*JmdictEntry {
+	entries := make([]*JmdictEntry, 0, len(indices))
+	for _, i := range indices {
+		entries = append(entries, &idx.dict.Entries[i])
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return jmdictPriorityScore(entries[i]) > jmdictPriorityScore(entries[j])
+	})
+
+	return entries
+}
+
+// LookupExpression returns the entries with a kanji element matching the
+// expression exactly, most common entries first.
+func (idx *JmdictIndex) LookupExpression(expression string) []*JmdictEntry {
+	return idx.entries(idx.expressions[expression])
+}
+
+// LookupReading returns the entries with a reading element matching the
+// reading exactly, most common entries first.
+func (idx *JmdictIndex) LookupReading(reading string) []*JmdictEntry {
+	return idx.entries(idx.readings[reading])
+}
+
+// LookupTerm returns the entries with a kanji or reading element matching
+// the term exactly, most common entries first.
+func (idx *JmdictIndex) LookupTerm(term string) []*JmdictEntry {
+	return idx.entries(mergeIndices(idx.expressions[term], idx.readings[term]))
+}
+
+// LookupGloss returns the entries with a gloss containing all of the words
+// of the query, ignoring case. Entries with a gloss equal to the query
+// (ignoring a leading "to" of verbs) are returned first, followed by the
+// remaining entries, each group ordered by commonness.
+func (idx *JmdictIndex) LookupGloss(query string) []*JmdictEntry {
+	words := glossWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	candidates := idx.glossWords[words[0]]
+	for _, word := range words[1:] {
+		candidates = intersectIndices(candidates, idx.glossWords[word])
+	}
+
+	target := strings.Join(trimGlossWords(words), " ")
+	entries := idx.entries(candidates)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return hasExactGloss(entries[i], target) && !hasExactGloss(entries[j], target)
+	})
+
+	return entries
+}
+
+func (idx *JmdictIndex) LookupSequence(sequence int) (*JmdictEntry, bool) {
+	if i, ok := idx.sequences[sequence]; ok {
+		return &idx.dict.Entries[i], true
+	}
+
+	return nil, false
+}
+
+// JmnedictIndex provides lookups of JMnedict entries by name, reading and
+// sequence number, with the same concurrency guarantees as JmdictIndex.
+type JmnedictIndex struct {
+	dic         *Jmnedict
+	expressions map[string][]int
+	readings    map[string][]int
+	sequences   map[int]int
+	types       map[NameType][]int
+}
+
+func NewJmnedictIndex(dic *Jmnedict) *JmnedictIndex {
+	idx := &JmnedictIndex{
+		dic:         dic,
+		expressions: make(map[string][]int),
+		readings:    make(map[string][]int),
+		sequences:   make(map[int]int),
+		types:       make(map[NameType][]int),
+	}
+
+	for i := range dic.Entries {
+		entry := &dic.Entries[i]
+		idx.sequences[entry.Sequence] = i
+
+		for _, t := range entry.NameTypes() {
+			idx.types[t] = append(idx.types[t], i)
+		}
+
+		for _, kanji := range entry.Kanji {
+			idx.expressions[kanji.Expression] = appendUnique(idx.expressions[kanji.Expression], i)
+		}
+
+		for _, reading := range entry.Readings {
+			idx.readings[reading.Reading] = appendUnique(idx.readings[reading.Reading], i)
+		}
+	}
+
+	return idx
+}
+
+func (idx *JmnedictIndex) entries(indices []int) []*JmnedictEntry {
+	entries := make([]*JmnedictEntry, 0, len(indices))
+	for _, i := range indices {
+		entries = append(entries, &idx.dic.Entries[i])
+	}
+
+	return entries
+}
+
+func (idx *JmnedictIndex) LookupExpression(expression string) []*JmnedictEntry {
+	return idx.entries(idx.expressions[expression])
+}
+
+func (idx *JmnedictIndex) LookupReading(reading string) []*JmnedictEntry {
+	return idx.entries(idx.readings[reading])
+}
+
+func (idx *JmnedictIndex) LookupTerm(term string) []*JmnedictEntry {
+	return idx.entries(mergeIndices(idx.expressions[term], idx.readings[term]))
+}
+
+func (idx *JmnedictIndex) LookupSequence(sequence int) (*JmnedictEntry, bool) {
+	if i, ok := idx.sequences[sequence]; ok {
+		return &idx.dic.Entries[i], true
+	}
+
+	return nil, false
+}
+
+// KanjiIndex provides lookups of KANJIDIC characters, with the same
+// concurrency guarantees as JmdictIndex.
+type KanjiIndex struct {
+	dic        *Kanjidic
+	literals   map[string]int
+	skip       map[SkipCode][]int
+	misclassed map[SkipCode][]int
+	fourCorner map[[4]int][]int
+	readings   map[string][]int
+	meanings   map[string][]int
+	codes      map[variantCode][]int
+	shinjitai  map[rune]rune
+}
+
+// NewKanjiIndex indexes the characters of the dictionary. Query codes which
+// do not parse are left out of the index.
+func NewKanjiIndex(dic *Kanjidic) *KanjiIndex {
+	idx := &KanjiIndex{
+		dic:        dic,
+		literals:   make(map[string]int),
+		skip:       make(map[SkipCode][]int),
+		misclassed: make(map[SkipCode][]int),
+		fourCorner: make(map[[4]int][]int),
+		readings:   make(map[string][]int),
+		meanings:   make(map[string][]int),
+		codes:      make(map[variantCode][]int),
+		shinjitai:  make(map[rune]rune),
+	}
+
+	for i := range dic.Characters {
+		character := &dic.Characters[i]
+		idx.literals[character.Literal] = i
+
+		for _, qc := range character.QueryCode {
+			switch qc.Type {
+			case "skip":
+				code, err := ParseSkipCode(qc.Value)
+				if err != nil {
+					continue
+				}
+				if qc.Misclassification == "" {
+					idx.skip[code] = appendUnique(idx.skip[code], i)
+				} else {
+					idx.misclassed[code] = appendUnique(idx.misclassed[code], i)
+				}
+			case "four_corner":
+				if code, err := ParseFourCornerCode(qc.Value); err == nil {
+					idx.fourCorner[code.Corners] = appendUnique(idx.fourCorner[code.Corners], i)
+				}
+			}
+		}
+
+		if rm := character.ReadingMeaning; rm != nil {
+			for _, group := range rm.Groups {
+				for _, reading := range group.Readings {
+					if reading.Type != "ja_on" && reading.Type != "ja_kun" {
+						continue
+					}
+					for _, key := range kanjiReadingKeys(reading.Value) {
+						idx.readings[key] = appendUnique(idx.readings[key], i)
+					}
+				}
+
+				for _, meaning := range group.Meanings {
+					for _, word := range glossWords(meaning.Meaning) {
+						idx.meanings[word] = appendUnique(idx.meanings[word], i)
+					}
+				}
+			}
+		}
+	}
+
+	idx.indexVariants()
+
+	return idx
+}
+
+func (idx *KanjiIndex) Lookup(literal string) (*KanjidicCharacter, bool) {
+	if i, ok := idx.literals[literal]; ok {
+		return &idx.dic.Characters[i], true
+	}
+
+	return nil, false
+}
+
+func (idx *KanjiIndex) characters(indices []int) []*KanjidicCharacter {
+	characters := make([]*KanjidicCharacter, 0, len(indices))
+	for _, i := range indices {
+		characters = append(characters, &idx.dic.Characters[i])
+	}
+
+	return characters
+}
+
+// LookupSkip returns the characters with the SKIP code. If misclassified
+// is set, characters for which the code is a common misclassification
+// (skip_misclass) are included as well, after the exact matches.
+func (idx *KanjiIndex) LookupSkip(code SkipCode, misclassified bool) []*KanjidicCharacter {
+	characters := idx.characters(idx.skip[code])
+	if misclassified {
+		for _, i := range idx.misclassed[code] {
+			character := &idx.dic.Characters[i]
+			if !containsCharacter(characters, character) {
+				characters = append(characters, character)
+			}
+		}
+	}
+
+	return characters
+}
+
+// LookupFourCorner returns the characters with the Four Corner code. The
+// fifth corner is only compared if the code has one.
+func (idx *KanjiIndex) LookupFourCorner(code FourCornerCode) []*KanjidicCharacter {
+	var indices []int
+	for _, i := range idx.fourCorner[code.Corners] {
+		if !code.HasFifth || hasFourCornerCode(&idx.dic.Characters[i], code) {
+			indices = append(indices, i)
+		}
+	}
+
+	return idx.characters(indices)
+}
+
+func hasFourCornerCode(character *KanjidicCharacter, code FourCornerCode) bool {
+	for _, qc := range character.QueryCode {
+		if qc.Type != "four_corner" {
+			continue
+		}
+		if c, err := ParseFourCornerCode(qc.Value); err == nil && c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsCharacter(characters []*KanjidicCharacter, character *KanjidicCharacter) bool {
+	for _, c := range characters {
+		if c == character {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jmdictPriorityScore ranks entries by the priority codes of their kanji
+// and reading elements: the common markers count the most, followed by the
+// word frequency rank (nf01 being the most frequent).
+func jmdictPriorityScore(entry *JmdictEntry) int {
+	var priorities []string
+	for _, kanji := range entry.Kanji {
+		priorities = append(priorities, kanji.Priorities...)
+	}
+
+	for _, reading := range entry.Readings {
+		priorities = append(priorities, reading.Priorities...)
+	}
+
+	return priorityScore(priorities)
+}
+
+func priorityScore(priorities []string) int {
+	var score int
+	seen := make(map[string]bool)
+	for _, priority := range priorities {
+		if seen[priority] {
+			continue
+		}
+		seen[priority] = true
+
+		switch {
+		case priority == "news1" || priority == "ichi1" || priority == "spec1" || priority == "gai1":
+			score += 100
+		case priority == "spec2":
+			score += 50
+		case priority == "news2" || priority == "ichi2" || priority == "gai2":
+			score += 10
+		case strings.HasPrefix(priority, "nf"):
+			var rank int
+			for _, c := range priority[2:] {
+				if c < '0' || c > '9' {
+					rank = 0
+					break
+				}
+				rank = rank*10 + int(c-'0')
+			}
+			if rank > 0 && rank <= 48 {
+				score += 49 - rank
+			}
+		}
+	}
+
+	return score
+}
+
+func glossWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c) && c != '\''
+	})
+}
+
+func trimGlossWords(words []string) []string {
+	if len(words) > 1 && words[0] == "to" {
+		return words[1:]
+	}
+
+	return words
+}
+
+func hasExactGloss(entry *JmdictEntry, target string) bool {
+	for _, sense := range entry.Sense {
+		for _, gloss := range sense.Glossary {
+			if strings.Join(trimGlossWords(glossWords(gloss.Content)), " ") == target {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func appendUnique(indices []int, i int) []int {
+	if len(indices) > 0 && indices[len(indices)-1] == i {
+		return indices
+	}
+
+	return append(indices, i)
+}
+
+// mergeIndices returns the sorted union of two sorted index lists.
+func mergeIndices(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 || len(b) > 0 {
+		switch {
+		case len(b) == 0 || (len(a) > 0 && a[0] < b[0]):
+			result, a = append(result, a[0]), a[1:]
+		case len(a) == 0 || b[0] < a[0]:
+			result, b = append(result, b[0]), b[1:]
+		default:
+			result, a, b = append(result, a[0]), a[1:], b[1:]
+		}
+	}
+
+	return result
+}
+
+// intersectIndices returns the sorted intersection of two sorted index lists.
+func intersectIndices(a, b []int) []int {
+	var result []int
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			a = a[1:]
+		case b[0] < a[0]:
+			b = b[1:]
+		default:
+			result, a, b = append(result, a[0]), a[1:], b[1:]
+		}
+	}
+
+	return result
+}
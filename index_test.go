@@ -0,0 +1,87 @@
+package jmdict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testJmdictIndex() *JmdictIndex {
+	rare := testEntry(1, "喰べる", "たべる", testSense("v1", "to eat", "to live on"))
+	common := testEntry(2, "食べる", "たべる", testSense("v1", "to eat"))
+	common.Kanji[0].Priorities = []string{"ichi1"}
+	school := testEntry(3, "学校", "がっこう", testSense("n", "school"))
+	lunch := testEntry(4, "給食", "きゅうしょく", testSense("n", "school lunch"))
+
+	return NewJmdictIndex(&Jmdict{Entries: []JmdictEntry{rare, common, school, lunch}})
+}
+
+func sequences(entries []*JmdictEntry) []int {
+	var result []int
+	for _, entry := range entries {
+		result = append(result, entry.Sequence)
+	}
+
+	return result
+}
+
+func TestJmdictIndexLookup(t *testing.T) {
+	idx := testJmdictIndex()
+
+	tests := []struct {
+		name string
+		got  []*JmdictEntry
+		want []int
+	}{
+		{"expression", idx.LookupExpression("学校"), []int{3}},
+		{"reading ordered by priority", idx.LookupReading("たべる"), []int{2, 1}},
+		{"term", idx.LookupTerm("給食"), []int{4}},
+		{"gloss exact first", idx.LookupGloss("School"), []int{3, 4}},
+		{"gloss all words", idx.LookupGloss("to live"), []int{1}},
+		{"gloss verb", idx.LookupGloss("eat"), []int{2, 1}},
+		{"missing", idx.LookupTerm("走る"), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := sequences(test.got); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if entry, ok := idx.LookupSequence(4); !ok || entry.Kanji[0].Expression != "給食" {
+		t.Errorf("LookupSequence(4) = %v, %v", entry, ok)
+	}
+
+	if _, ok := idx.LookupSequence(5); ok {
+		t.Error("LookupSequence(5) found an entry")
+	}
+}
+
+func TestJmnedictIndexLookup(t *testing.T) {
+	idx := NewJmnedictIndex(&Jmnedict{Entries: []JmnedictEntry{{
+		Sequence: 5000001,
+		Kanji:    []JmnedictKanji{{Expression: "東京"}},
+		Readings: []JmnedictReading{{Reading: "とうきょう"}},
+	}}})
+
+	if entries := idx.LookupTerm("とうきょう"); len(entries) != 1 || entries[0].Sequence != 5000001 {
+		t.Errorf("LookupTerm = %v", entries)
+	}
+
+	if entries := idx.LookupExpression("京都"); len(entries) != 0 {
+		t.Errorf("LookupExpression = %v, want none", entries)
+	}
+}
+
+func TestKanjiIndexLookup(t *testing.T) {
+	idx := NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{{Literal: "食"}}})
+
+	if character, ok := idx.Lookup("食"); !ok || character.Literal != "食" {
+		t.Errorf("Lookup(食) = %v, %v", character, ok)
+	}
+
+	if _, ok := idx.Lookup("喰"); ok {
+		t.Error("Lookup(喰) found a character")
+	}
+}
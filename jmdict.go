@@ -1,12 +1,18 @@
 package jmdict
 
-import "io"
+import (
+	"encoding/xml"
+	"io"
+)
 
 type Jmdict struct {
 	// Entries consist of kanji elements, reading elements,
 	// general information and sense elements. Each entry must have at
 	// least one reading element and one sense element. Others are optional.
 	Entries []JmdictEntry `xml:"entry"`
+
+	// Information about the source file, such as its creation date.
+	Info DictionaryInfo `xml:"-"`
 }
 
 type JmdictEntry struct {
@@ -128,6 +134,11 @@ type JmdictSource struct {
 	// Otherwise it will contain "part".
 	Type *string `xml:"ls_type,attr"`
 
+	// Set when Language or Type were absent from the file and filled in
+	// with their implied values by the WithDefaults load option.
+	LanguageImplied bool `xml:"-" json:"-"`
+	TypeImplied     bool `xml:"-" json:"-"`
+
 	// The ls_wasei attribute indicates that the Japanese word
 	// has been constructed from words in the source language, and
 	// not from an actual phrase in that language. Most commonly used to
@@ -144,10 +155,14 @@ type JmdictGlossary struct {
 	// is the default value.
 	Language *string `xml:"lang,attr"`
 
+	// Set when Language was absent from the file and filled in with its
+	// implied value by the WithDefaults load option.
+	LanguageImplied bool `xml:"-" json:"-"`
+
 	// The g_gend attribute defines the gender of the gloss (typically
 	// a noun in the target language. When absent, the gender is either
 	// not relevant or has yet to be provided.
-	Gender *string `xml:"g_gend"`
+	Gender *string `xml:"g_gend,attr"`
 
 	// g_type attribute added in jmdict Rev 1.09
 	// At present the values used are "lit", "fig", "expl" and "tm". It is
@@ -247,14 +262,56 @@ type JmdictExampleSentence struct {
 	Text string `xml:",chardata"`
 }
 
-func LoadJmdict(reader io.Reader) (Jmdict, map[string]string, error) {
-	var dict Jmdict
-	entities, err := parseDict(reader, &dict, true)
-	return dict, entities, err
+func LoadJmdict(reader io.Reader, options ...LoadOption) (Jmdict, map[string]string, error) {
+	return loadJmdict(reader, true, options)
+}
+
+func LoadJmdictNoTransform(reader io.Reader, options ...LoadOption) (Jmdict, map[string]string, error) {
+	return loadJmdict(reader, false, options)
+}
+
+// StreamJmdict decodes the dictionary one entry at a time, invoking the
+// callback with each entry that remains after the load options have been
+// applied. Entries are not retained, so the callback may keep the pointer.
+// Returning an error from the callback stops decoding.
+func StreamJmdict(reader io.Reader, callback func(*JmdictEntry) error, options ...LoadOption) (DictionaryInfo, map[string]string, error) {
+	return streamJmdict(reader, true, callback, options)
 }
 
-func LoadJmdictNoTransform(reader io.Reader) (Jmdict, map[string]string, error) {
+func StreamJmdictNoTransform(reader io.Reader, callback func(*JmdictEntry) error, options ...LoadOption) (DictionaryInfo, map[string]string, error) {
+	return streamJmdict(reader, false, callback, options)
+}
+
+func loadJmdict(reader io.Reader, transform bool, options []LoadOption) (Jmdict, map[string]string, error) {
 	var dict Jmdict
-	entities, err := parseDict(reader, &dict, false)
+	info, entities, err := streamJmdict(reader, transform, func(entry *JmdictEntry) error {
+		dict.Entries = append(dict.Entries, *entry)
+		return nil
+	}, options)
+
+	dict.Info = info
 	return dict, entities, err
 }
+
+func streamJmdict(reader io.Reader, transform bool, callback func(*JmdictEntry) error, options []LoadOption) (DictionaryInfo, map[string]string, error) {
+	opts := newLoadOptions(options)
+	info, entities, err := parseDict(reader, transform, func(decoder *xml.Decoder, element *xml.StartElement, info *DictionaryInfo) error {
+		if element.Name.Local != "entry" {
+			return decoder.Skip()
+		}
+
+		entry := new(JmdictEntry)
+		if err := decoder.DecodeElement(entry, element); err != nil {
+			return err
+		}
+
+		if !opts.processJmdict(entry, info) {
+			return nil
+		}
+
+		return callback(entry)
+	})
+
+	info.Languages = opts.languageList()
+	return info, entities, err
+}
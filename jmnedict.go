@@ -1,12 +1,18 @@
 package jmdict
 
-import "io"
+import (
+	"encoding/xml"
+	"io"
+)
 
 type Jmnedict struct {
 	// Entries consist of kanji elements, reading elements
 	// name translation elements. Each entry must have at
 	// least one reading element and one sense element. Others are optional.
 	Entries []JmnedictEntry `xml:"entry"`
+
+	// Information about the source file, such as its creation date.
+	Info DictionaryInfo `xml:"-"`
 }
 
 type JmnedictEntry struct {
@@ -106,16 +112,58 @@ type JmnedictTranslation struct {
 	// (i.e. English) is the default value. The bibliographic (B) codes
 	// are used.
 	Language *string `xml:"lang,attr"`
+
+	// Set when Language was absent from the file and filled in with its
+	// implied value by the WithDefaults load option.
+	LanguageImplied bool `xml:"-" json:"-"`
 }
 
-func LoadJmnedict(reader io.Reader) (Jmnedict, map[string]string, error) {
-	var dic Jmnedict
-	entities, err := parseDict(reader, &dic, true)
-	return dic, entities, err
+func LoadJmnedict(reader io.Reader, options ...LoadOption) (Jmnedict, map[string]string, error) {
+	return loadJmnedict(reader, true, options)
 }
 
-func LoadJmnedictNoTransform(reader io.Reader) (Jmnedict, map[string]string, error) {
+func LoadJmnedictNoTransform(reader io.Reader, options ...LoadOption) (Jmnedict, map[string]string, error) {
+	return loadJmnedict(reader, false, options)
+}
+
+func StreamJmnedict(reader io.Reader, callback func(*JmnedictEntry) error, options ...LoadOption) (DictionaryInfo, map[string]string, error) {
+	return streamJmnedict(reader, true, callback, options)
+}
+
+func StreamJmnedictNoTransform(reader io.Reader, callback func(*JmnedictEntry) error, options ...LoadOption) (DictionaryInfo, map[string]string, error) {
+	return streamJmnedict(reader, false, callback, options)
+}
+
+func loadJmnedict(reader io.Reader, transform bool, options []LoadOption) (Jmnedict, map[string]string, error) {
 	var dic Jmnedict
-	entities, err := parseDict(reader, &dic, false)
+	info, entities, err := streamJmnedict(reader, transform, func(entry *JmnedictEntry) error {
+		dic.Entries = append(dic.Entries, *entry)
+		return nil
+	}, options)
+
+	dic.Info = info
 	return dic, entities, err
 }
+
+func streamJmnedict(reader io.Reader, transform bool, callback func(*JmnedictEntry) error, options []LoadOption) (DictionaryInfo, map[string]string, error) {
+	opts := newLoadOptions(options)
+	info, entities, err := parseDict(reader, transform, func(decoder *xml.Decoder, element *xml.StartElement, info *DictionaryInfo) error {
+		if element.Name.Local != "entry" {
+			return decoder.Skip()
+		}
+
+		entry := new(JmnedictEntry)
+		if err := decoder.DecodeElement(entry, element); err != nil {
+			return err
+		}
+
+		if !opts.processJmnedict(entry, info) {
+			return nil
+		}
+
+		return callback(entry)
+	})
+
+	info.Languages = opts.languageList()
+	return info, entities, err
+}
@@ -0,0 +1,45 @@
+package jmdict
+
+import "strings"
+
+// isKana reports whether the character may appear in a reading element:
+// hiragana, katakana (including the small katakana extensions), the
+// prolonged sound mark, the middle dot and the kana iteration marks.
+func isKana(c rune) bool {
+	return (c >= 0x3041 && c <= 0x309f) || (c >= 0x30a0 && c <= 0x30ff) || (c >= 0x31f0 && c <= 0x31ff)
+}
+
+// isReadingRune reports whether the character may appear in a reading
+// element. Besides kana, readings contain the full-width Latin letters and
+// digits of words such as ＣＤ or ３Ｄ, and some punctuation (＝, 〜, 、).
+func isReadingRune(c rune) bool {
+	switch {
+	case isKana(c):
+		return true
+	case c >= '０' && c <= '９', c >= 'Ａ' && c <= 'Ｚ', c >= 'ａ' && c <= 'ｚ':
+		return true
+	default:
+		return strings.ContainsRune("＝〜～、。〇", c)
+	}
+}
+
+func isReadingString(text string) bool {
+	for _, c := range text {
+		if !isReadingRune(c) {
+			return false
+		}
+	}
+
+	return text != ""
+}
+
+// katakanaToHiragana converts the katakana in the text which have hiragana
+// equivalents, leaving all other characters (including ー) unchanged.
+func katakanaToHiragana(text string) string {
+	return strings.Map(func(c rune) rune {
+		if (c >= 0x30a1 && c <= 0x30f6) || c == 0x30fd || c == 0x30fe {
+			return c - 0x60
+		}
+		return c
+	}, text)
+}
@@ -0,0 +1,33 @@
+package jmdict
+
+import "testing"
+
+func TestIsReadingString(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"たべる", true},
+		{"カタカナ", true},
+		{"ア・ラ・カルト", true},
+		{"ラーメン", true},
+		{"ㇰ", true},
+		{"シー・ディー＝ロム", true},
+		{"ＣＤ", true},
+		{"３ＤＳ", true},
+		{"ｅメール", true},
+		{"〜ずつ", true},
+		{"", false},
+		{"gakkou", false},
+		{"CD", false},
+		{"3D", false},
+		{"食べる", false},
+		{"たべ る", false},
+	}
+
+	for _, test := range tests {
+		if got := isReadingString(test.text); got != test.want {
+			t.Errorf("isReadingString(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package jmdict
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingValue is returned by the accessors of KanjidicCharacter for
+// fields which every character is expected to have, but which are absent.
+var ErrMissingValue = errors.New("missing value")
+
+// Grade returns the grade in which the character is taught (1-6 for the
+// kyouiku kanji, 8 for the remaining jouyou kanji and 9-10 for the
+// jinmeiyou kanji). The second result is false if the character has no
+// grade.
+func (c *KanjidicCharacter) Grade() (int, bool, error) {
+	return c.optionalInt("grade", c.Misc.Grade)
+}
+
+// StrokeCount returns the accepted stroke count of the character.
+func (c *KanjidicCharacter) StrokeCount() (int, error) {
+	counts, err := c.strokeCounts()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(counts) == 0 {
+		return 0, c.missing("stroke count")
+	}
+
+	return counts[0], nil
+}
+
+// Miscounts returns the common miscounts of the strokes of the character,
+// which follow the accepted count in the stroke_count elements.
+func (c *KanjidicCharacter) Miscounts() ([]int, error) {
+	counts, err := c.strokeCounts()
+	if err != nil || len(counts) < 2 {
+		return nil, err
+	}
+
+	return counts[1:], nil
+}
+
+// Frequency returns the rank of the character among the 2,500 most used
+// in newspapers. The second result is false if the character is unranked.
+func (c *KanjidicCharacter) Frequency() (int, bool, error) {
+	return c.optionalInt("frequency", c.Misc.Frequency)
+}
+
+// JLPT returns the level of the character in the former, four level JLPT.
+// The second result is false if the character was not part of the test.
+func (c *KanjidicCharacter) JLPT() (int, bool, error) {
+	return c.optionalInt("jlpt", c.Misc.JlptLevel)
+}
+
+// ClassicalRadical returns the number of the KangXi Zidian radical of the
+// character.
+func (c *KanjidicCharacter) ClassicalRadical() (int, error) {
+	for _, radical := range c.Radical {
+		if radical.Type == "classical" {
+			return c.parseInt("classical radical", radical.Value)
+		}
+	}
+
+	return 0, c.missing("classical radical")
+}
+
+// Unicode returns the code point recorded for the character, which is
+// normally the literal itself.
+func (c *KanjidicCharacter) Unicode() (rune, error) {
+	value, ok := c.codepoint("ucs")
+	if !ok {
+		return 0, c.missing("ucs codepoint")
+	}
+
+	n, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return 0, c.invalid("ucs codepoint", value)
+	}
+
+	return rune(n), nil
+}
+
+// JIS208 returns the plane, row (kuten ku) and cell (kuten ten) of the
+// character in JIS X 0208.
+func (c *KanjidicCharacter) JIS208() (plane, row, cell int, err error) {
+	value, ok := c.codepoint("jis208")
+	if !ok {
+		return 0, 0, 0, c.missing("jis208 codepoint")
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) == 2 {
+		parts = append([]string{"1"}, parts...)
+	}
+
+	var numbers [3]int
+	if len(parts) != len(numbers) {
+		return 0, 0, 0, c.invalid("jis208 codepoint", value)
+	}
+
+	for i, part := range parts {
+		if numbers[i], err = strconv.Atoi(part); err != nil {
+			return 0, 0, 0, c.invalid("jis208 codepoint", value)
+		}
+	}
+
+	return numbers[0], numbers[1], numbers[2], nil
+}
+
+func (c *KanjidicCharacter) strokeCounts() ([]int, error) {
+	counts := make([]int, 0, len(c.Misc.StrokeCounts))
+	for _, value := range c.Misc.StrokeCounts {
+		count, err := c.parseInt("stroke count", value)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func (c *KanjidicCharacter) codepoint(kind string) (string, bool) {
+	for _, cp := range c.Codepoint {
+		if cp.Type == kind {
+			return cp.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *KanjidicCharacter) optionalInt(field string, value *string) (int, bool, error) {
+	if value == nil {
+		return 0, false, nil
+	}
+
+	n, err := c.parseInt(field, *value)
+	return n, err == nil, err
+}
+
+func (c *KanjidicCharacter) parseInt(field, value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, c.invalid(field, value)
+	}
+
+	return n, nil
+}
+
+func (c *KanjidicCharacter) invalid(field, value string) error {
+	return fmt.Errorf("kanjidic character %s: invalid %s %q", c.Literal, field, value)
+}
+
+func (c *KanjidicCharacter) missing(field string) error {
+	return fmt.Errorf("kanjidic character %s: %s: %w", c.Literal, field, ErrMissingValue)
+}
+
+// KunReading is a kun reading of KANJIDIC2, such as "た.べる", with the
+// okurigana and affix markers parsed. The stem is the part of the reading
+// written with the kanji.
+type KunReading struct {
+	Stem      string
+	Okurigana string
+
+	// A prefix reading ("お-") is followed by the word it attaches to, and
+	// a suffix reading ("-ぐ") follows it.
+	IsPrefix bool
+	IsSuffix bool
+}
+
+func ParseKunReading(value string) KunReading {
+	var reading KunReading
+
+	if strings.HasPrefix(value, "-") {
+		reading.IsSuffix = true
+		value = value[1:]
+	}
+
+	if strings.HasSuffix(value, "-") {
+		reading.IsPrefix = true
+		value = value[:len(value)-1]
+	}
+
+	reading.Stem, reading.Okurigana, _ = strings.Cut(value, ".")
+	return reading
+}
+
+// String returns the reading in the KANJIDIC2 notation.
+func (r KunReading) String() string {
+	text := r.Stem
+	if r.Okurigana != "" {
+		text += "." + r.Okurigana
+	}
+	if r.IsSuffix {
+		text = "-" + text
+	}
+	if r.IsPrefix {
+		text += "-"
+	}
+
+	return text
+}
+
+// Reading returns the reading of the word as a whole, without markers.
+func (r KunReading) Reading() string {
+	return r.Stem + r.Okurigana
+}
+
+// WrittenForms returns the ways of writing the word with the kanji: first
+// the standard form (食 and た.べる give 食べる), followed by the variants
+// the okurigana rules allow, which JMdict often lists as well. Okurigana
+// longer than the inflected ending may drop its first kana (生れる for
+// 生まれる), and okurigana which is only the ending may take on the last
+// kana of the stem (行なう for 行う). Readings without okurigana only have
+// the literal itself.
+func (r KunReading) WrittenForms(literal string) []string {
+	forms := []string{literal + r.Okurigana}
+	if r.Okurigana == "" {
+		return forms
+	}
+
+	okurigana := []rune(r.Okurigana)
+	ending := okuriganaEnding(okurigana)
+	if len(okurigana) > ending {
+		forms = append(forms, literal+string(okurigana[1:]))
+	}
+
+	if stem := []rune(r.Stem); len(okurigana) == ending && len(stem) > 1 {
+		forms = append(forms, literal+string(stem[len(stem)-1:])+r.Okurigana)
+	}
+
+	return forms
+}
+
+// okuriganaEnding returns the number of kana at the end of the okurigana
+// which inflect or belong to the inflected ending and are always written:
+// the -eru and -iru of ichidan verbs (べる), the しい of adjectives and
+// otherwise the last kana.
+func okuriganaEnding(okurigana []rune) int {
+	if n := len(okurigana); n >= 2 {
+		last, prev := okurigana[n-1], okurigana[n-2]
+		if last == 'る' && strings.ContainsRune("いきぎしじちぢにひびぴみりえけげせぜてでねへべぺめれ", prev) {
+			return 2
+		}
+		if last == 'い' && prev == 'し' {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// KunReadings returns the parsed kun readings of the character.
+func (c *KanjidicCharacter) KunReadings() []KunReading {
+	if c.ReadingMeaning == nil {
+		return nil
+	}
+
+	var readings []KunReading
+	for _, group := range c.ReadingMeaning.Groups {
+		for _, reading := range group.Readings {
+			if reading.Type == "ja_kun" {
+				readings = append(readings, ParseKunReading(reading.Value))
+			}
+		}
+	}
+
+	return readings
+}
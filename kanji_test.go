@@ -0,0 +1,145 @@
+package jmdict
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testKanjidicCharacter() KanjidicCharacter {
+	grade, frequency := "2", "328"
+	return KanjidicCharacter{
+		Literal: "食",
+		Codepoint: []KanjidicCodepoint{
+			{Value: "98df", Type: "ucs"},
+			{Value: "31-9", Type: "jis208"},
+		},
+		Radical: []KanjidicRadical{{Value: "184", Type: "classical"}, {Value: "9", Type: "nelson_c"}},
+		Misc:    KanjidicMisc{Grade: &grade, Frequency: &frequency, StrokeCounts: []string{"9", "10"}},
+	}
+}
+
+func TestKanjidicCharacterAccessors(t *testing.T) {
+	character := testKanjidicCharacter()
+
+	if grade, ok, err := character.Grade(); grade != 2 || !ok || err != nil {
+		t.Errorf("Grade() = %d, %v, %v", grade, ok, err)
+	}
+
+	if level, ok, err := character.JLPT(); level != 0 || ok || err != nil {
+		t.Errorf("JLPT() = %d, %v, %v, want no level", level, ok, err)
+	}
+
+	if frequency, ok, err := character.Frequency(); frequency != 328 || !ok || err != nil {
+		t.Errorf("Frequency() = %d, %v, %v", frequency, ok, err)
+	}
+
+	if count, err := character.StrokeCount(); count != 9 || err != nil {
+		t.Errorf("StrokeCount() = %d, %v", count, err)
+	}
+
+	if miscounts, err := character.Miscounts(); !reflect.DeepEqual(miscounts, []int{10}) || err != nil {
+		t.Errorf("Miscounts() = %v, %v", miscounts, err)
+	}
+
+	if radical, err := character.ClassicalRadical(); radical != 184 || err != nil {
+		t.Errorf("ClassicalRadical() = %d, %v", radical, err)
+	}
+
+	if r, err := character.Unicode(); r != '食' || err != nil {
+		t.Errorf("Unicode() = %q, %v", r, err)
+	}
+
+	if plane, row, cell, err := character.JIS208(); plane != 1 || row != 31 || cell != 9 || err != nil {
+		t.Errorf("JIS208() = %d, %d, %d, %v", plane, row, cell, err)
+	}
+}
+
+func TestKanjidicCharacterAccessorErrors(t *testing.T) {
+	character := testKanjidicCharacter()
+	invalid := "second"
+	character.Misc.Grade = &invalid
+	character.Codepoint = []KanjidicCodepoint{{Value: "1-31", Type: "jis208"}}
+	character.Radical = nil
+
+	if _, ok, err := character.Grade(); ok || err == nil || !strings.Contains(err.Error(), `invalid grade "second"`) {
+		t.Errorf("Grade() = %v, %v", ok, err)
+	}
+
+	if _, err := character.ClassicalRadical(); !errors.Is(err, ErrMissingValue) {
+		t.Errorf("ClassicalRadical() error = %v, want ErrMissingValue", err)
+	}
+
+	if _, err := character.Unicode(); !errors.Is(err, ErrMissingValue) {
+		t.Errorf("Unicode() error = %v, want ErrMissingValue", err)
+	}
+
+	character.Codepoint[0].Value = "1-x-9"
+	if _, _, _, err := character.JIS208(); err == nil || errors.Is(err, ErrMissingValue) {
+		t.Errorf("JIS208() error = %v, want an invalid value", err)
+	}
+}
+
+func TestParseKunReading(t *testing.T) {
+	tests := []struct {
+		value string
+		want  KunReading
+		whole string
+	}{
+		{"た.べる", KunReading{Stem: "た", Okurigana: "べる"}, "たべる"},
+		{"なま", KunReading{Stem: "なま"}, "なま"},
+		{"-ぐ", KunReading{Stem: "ぐ", IsSuffix: true}, "ぐ"},
+		{"つ.ける-", KunReading{Stem: "つ", Okurigana: "ける", IsPrefix: true}, "つける"},
+	}
+
+	for _, test := range tests {
+		reading := ParseKunReading(test.value)
+		if reading != test.want {
+			t.Errorf("ParseKunReading(%q) = %+v, want %+v", test.value, reading, test.want)
+		}
+
+		if reading.String() != test.value {
+			t.Errorf("String() = %q, want %q", reading.String(), test.value)
+		}
+
+		if reading.Reading() != test.whole {
+			t.Errorf("Reading() = %q, want %q", reading.Reading(), test.whole)
+		}
+	}
+}
+
+func TestKunReadingWrittenForms(t *testing.T) {
+	tests := []struct {
+		literal string
+		reading string
+		want    []string
+	}{
+		{"食", "た.べる", []string{"食べる"}},
+		{"生", "う.まれる", []string{"生まれる", "生れる"}},
+		{"終", "お.わる", []string{"終わる", "終る"}},
+		{"行", "おこな.う", []string{"行う", "行なう"}},
+		{"表", "あらわ.す", []string{"表す", "表わす"}},
+		{"美", "うつく.しい", []string{"美しい", "美くしい"}},
+		{"生", "なま", []string{"生"}},
+	}
+
+	for _, test := range tests {
+		if got := ParseKunReading(test.reading).WrittenForms(test.literal); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s %s: WrittenForms = %v, want %v", test.literal, test.reading, got, test.want)
+		}
+	}
+}
+
+func TestKanjidicCharacterKunReadings(t *testing.T) {
+	character := testCharacter("食", "ショク,た.べる,く.う", "eat")
+
+	var got []string
+	for _, reading := range character.KunReadings() {
+		got = append(got, reading.String())
+	}
+
+	if !reflect.DeepEqual(got, []string{"た.べる", "く.う"}) {
+		t.Errorf("KunReadings = %v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package jmdict
 
-import "io"
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+)
 
 type Kanjidic struct {
 	// The single header element will contain identification information
@@ -8,6 +12,10 @@ type Kanjidic struct {
 	Header KanjidicHeader `xml:"header"`
 
 	Characters []KanjidicCharacter `xml:"character"`
+
+	// Information about the source file. The creation date is taken
+	// from the header.
+	Info DictionaryInfo `xml:"-"`
 }
 
 type KanjidicHeader struct {
@@ -141,7 +149,7 @@ type KanjidicVariant struct {
 	// 	nelson_c - "Classic" Nelson - numeric
 	// 	oneill - Japanese Names (O'Neill) - numeric
 	// 	ucs - Unicode codepoint- hex
-	Type string `xml:"var_type"`
+	Type string `xml:"var_type,attr"`
 }
 
 type KanjidicDicNumber struct {
@@ -239,15 +247,64 @@ type KanjidicQueryCode struct {
 }
 
 type KanjidicReadingMeaning struct {
+	// The readings and meanings of the character, grouped as in the
+	// rmgroup elements. A character with several unrelated senses has a
+	// group for each, with the readings used for that sense.
+	Groups []KanjidicRMGroup `xml:"rmgroup"`
+
+	// Japanese readings that are now only associated with names.
+	Nanori []string `xml:"nanori"`
+}
+
+type KanjidicRMGroup struct {
 	// The reading element contains the reading or pronunciation
 	// of the kanji.
-	Readings []KanjidicReading `xml:"rmgroup>reading"`
+	Readings []KanjidicReading `xml:"reading"`
 
 	// The meaning associated with the kanji.
-	Meanings []KanjidicMeaning `xml:"rmgroup>meaning"`
+	Meanings []KanjidicMeaning `xml:"meaning"`
+}
 
-	// Japanese readings that are now only associated with names.
-	Nanori []string `xml:"nanori"`
+// Readings returns the readings of all groups, in order.
+func (rm *KanjidicReadingMeaning) Readings() []KanjidicReading {
+	var readings []KanjidicReading
+	for _, group := range rm.Groups {
+		readings = append(readings, group.Readings...)
+	}
+
+	return readings
+}
+
+// Meanings returns the meanings of all groups, in order.
+func (rm *KanjidicReadingMeaning) Meanings() []KanjidicMeaning {
+	var meanings []KanjidicMeaning
+	for _, group := range rm.Groups {
+		meanings = append(meanings, group.Meanings...)
+	}
+
+	return meanings
+}
+
+// UnmarshalJSON also accepts the JSON written before the groups were
+// preserved, whose flat Readings and Meanings become a single group.
+func (rm *KanjidicReadingMeaning) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Groups   []KanjidicRMGroup
+		Readings []KanjidicReading
+		Meanings []KanjidicMeaning
+		Nanori   []string
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	rm.Groups, rm.Nanori = decoded.Groups, decoded.Nanori
+	if len(rm.Groups) == 0 && (len(decoded.Readings) > 0 || len(decoded.Meanings) > 0) {
+		rm.Groups = []KanjidicRMGroup{{Readings: decoded.Readings, Meanings: decoded.Meanings}}
+	}
+
+	return nil
 }
 
 type KanjidicReading struct {
@@ -278,10 +335,10 @@ type KanjidicReading struct {
 	Type string `xml:"r_type,attr"`
 
 	// See under ja_on above.
-	OnType *string `xml:"on_type"`
+	OnType *string `xml:"on_type,attr"`
 
 	// See under ja_on and ja_kun above.
-	JouyouStatus *string `xml:"r_status"`
+	JouyouStatus *string `xml:"r_status,attr"`
 }
 
 type KanjidicMeaning struct {
@@ -294,8 +351,49 @@ type KanjidicMeaning struct {
 	Language *string `xml:"m_lang,attr"`
 }
 
-func LoadKanjidic(reader io.Reader) (Kanjidic, error) {
+func LoadKanjidic(reader io.Reader, options ...LoadOption) (Kanjidic, error) {
 	var dic Kanjidic
-	_, err := parseDict(reader, &dic, true)
+	header, info, err := StreamKanjidic(reader, func(character *KanjidicCharacter) error {
+		dic.Characters = append(dic.Characters, *character)
+		return nil
+	}, options...)
+
+	dic.Header = header
+	dic.Info = info
 	return dic, err
 }
+
+// StreamKanjidic decodes the dictionary one character at a time, invoking
+// the callback with each character after the load options have been
+// applied. The header is returned once the whole file has been read.
+func StreamKanjidic(reader io.Reader, callback func(*KanjidicCharacter) error, options ...LoadOption) (KanjidicHeader, DictionaryInfo, error) {
+	opts := newLoadOptions(options)
+
+	var header KanjidicHeader
+	info, _, err := parseDict(reader, true, func(decoder *xml.Decoder, element *xml.StartElement, info *DictionaryInfo) error {
+		switch element.Name.Local {
+		case "header":
+			return decoder.DecodeElement(&header, element)
+		case "character":
+			character := new(KanjidicCharacter)
+			if err := decoder.DecodeElement(character, element); err != nil {
+				return err
+			}
+
+			if !opts.processKanjidic(character, info) {
+				return nil
+			}
+
+			return callback(character)
+		default:
+			return decoder.Skip()
+		}
+	})
+
+	if header.DateOfCreation != "" {
+		info.Created = header.DateOfCreation
+	}
+	info.Languages = opts.languageList()
+
+	return header, info, err
+}
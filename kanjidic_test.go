@@ -0,0 +1,113 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testKanjidicXML = `<?xml version="1.0" encoding="UTF-8"?>
+<kanjidic2>
+<header><file_version>4</file_version><date_of_creation>2022-07-14</date_of_creation></header>
+<character>
+<literal>生</literal>
+<reading_meaning>
+<rmgroup>
+<reading r_type="ja_on">セイ</reading>
+<reading r_type="ja_kun">い.きる</reading>
+<meaning>life</meaning>
+<meaning m_lang="fr">vie</meaning>
+</rmgroup>
+<rmgroup>
+<reading r_type="ja_kun">なま</reading>
+<meaning>raw</meaning>
+</rmgroup>
+<nanori>いく</nanori>
+</reading_meaning>
+</character>
+</kanjidic2>`
+
+func kanjidicValues(rm *KanjidicReadingMeaning) (readings, meanings []string) {
+	for _, reading := range rm.Readings() {
+		readings = append(readings, reading.Value)
+	}
+	for _, meaning := range rm.Meanings() {
+		meanings = append(meanings, meaning.Meaning)
+	}
+
+	return readings, meanings
+}
+
+func TestLoadKanjidicRMGroups(t *testing.T) {
+	dic, err := LoadKanjidic(strings.NewReader(testKanjidicXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rm := dic.Characters[0].ReadingMeaning
+	if len(rm.Groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(rm.Groups))
+	}
+
+	if group := rm.Groups[1]; len(group.Readings) != 1 || group.Readings[0].Value != "なま" || group.Meanings[0].Meaning != "raw" {
+		t.Errorf("Groups[1] = %+v", group)
+	}
+
+	readings, meanings := kanjidicValues(rm)
+	if !reflect.DeepEqual(readings, []string{"セイ", "い.きる", "なま"}) || !reflect.DeepEqual(meanings, []string{"life", "vie", "raw"}) {
+		t.Errorf("flat readings %v, meanings %v", readings, meanings)
+	}
+
+	if !reflect.DeepEqual(rm.Nanori, []string{"いく"}) {
+		t.Errorf("Nanori = %v", rm.Nanori)
+	}
+}
+
+func TestKanjidicReadingMeaningJSON(t *testing.T) {
+	dic, err := LoadKanjidic(strings.NewReader(testKanjidicXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(dic.Characters[0].ReadingMeaning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "セイ"); n != 1 {
+		t.Errorf("JSON %s repeats the readings outside of the groups", data)
+	}
+
+	var rm KanjidicReadingMeaning
+	if err := json.Unmarshal(data, &rm); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&rm, dic.Characters[0].ReadingMeaning) {
+		t.Errorf("decoded %+v, want %+v", rm, dic.Characters[0].ReadingMeaning)
+	}
+
+	// JSON written before the groups were kept has the flat slices alone.
+	legacy := `{"Readings":[{"Value":"セイ","Type":"ja_on"}],"Meanings":[{"Meaning":"life"}],"Nanori":["いく"]}`
+	if err := json.Unmarshal([]byte(legacy), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if readings, meanings := kanjidicValues(&rm); len(rm.Groups) != 1 || !reflect.DeepEqual(readings, []string{"セイ"}) || !reflect.DeepEqual(meanings, []string{"life"}) {
+		t.Errorf("decoded legacy JSON as %+v", rm)
+	}
+}
+
+func TestFilterKanjidicLanguagesKeepsGroups(t *testing.T) {
+	dic, err := LoadKanjidic(strings.NewReader(testKanjidicXML), WithLanguages("eng"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rm := dic.Characters[0].ReadingMeaning
+	if len(rm.Groups) != 2 || len(rm.Groups[0].Meanings) != 1 || rm.Groups[1].Meanings[0].Meaning != "raw" {
+		t.Errorf("Groups = %+v", rm.Groups)
+	}
+
+	if _, meanings := kanjidicValues(rm); !reflect.DeepEqual(meanings, []string{"life", "raw"}) {
+		t.Errorf("flat meanings = %v", meanings)
+	}
+}
@@ -0,0 +1,220 @@
+package jmdict
+
+import "sort"
+
+// KanjiQuery holds the criteria of a kanji search. Zero values match any
+// character; a character must satisfy all of the given criteria.
+type KanjiQuery struct {
+	// Inclusive range of stroke counts. If Miscounts is set, characters
+	// whose strokes are commonly miscounted as a number in the range match
+	// as well.
+	MinStrokes int
+	MaxStrokes int
+	Miscounts  bool
+
+	// Number of the classical (KangXi Zidian) radical.
+	Radical int
+
+	// Any of the grades or (former) JLPT levels.
+	Grades []int
+	JLPT   []int
+
+	// Only characters ranked at or above this newspaper frequency rank.
+	MaxFrequency int
+
+	// An on or kun reading, in hiragana or katakana. Kun readings match
+	// with or without their okurigana (た matches た.べる, as does たべる).
+	Reading string
+
+	// Words which must all appear in one of the meanings, ignoring case.
+	Meaning string
+
+	// A dictionary reference type (dr_type, e.g. "heisig" or "nelson_c"),
+	// and optionally the number of the character in that dictionary.
+	Reference       string
+	ReferenceNumber string
+}
+
+// Search returns the characters matching the query, the most frequently
+// used first and characters without a frequency rank last.
+func (idx *KanjiIndex) Search(query KanjiQuery) []*KanjidicCharacter {
+	var (
+		candidates []int
+		narrowed   bool
+	)
+
+	narrow := func(indices []int) {
+		if narrowed {
+			candidates = intersectIndices(candidates, indices)
+		} else {
+			candidates, narrowed = indices, true
+		}
+	}
+
+	if query.Reading != "" {
+		narrow(idx.readings[normalizeKanjiReading(query.Reading)])
+	}
+
+	for _, word := range glossWords(query.Meaning) {
+		narrow(idx.meanings[word])
+	}
+
+	if !narrowed {
+		candidates = make([]int, len(idx.dic.Characters))
+		for i := range candidates {
+			candidates[i] = i
+		}
+	}
+
+	var characters []*KanjidicCharacter
+	for _, i := range candidates {
+		if character := &idx.dic.Characters[i]; query.matches(character) {
+			characters = append(characters, character)
+		}
+	}
+
+	sort.SliceStable(characters, func(i, j int) bool {
+		fi, oki, _ := characters[i].Frequency()
+		fj, okj, _ := characters[j].Frequency()
+		return oki && (!okj || fi < fj)
+	})
+
+	return characters
+}
+
+func (query *KanjiQuery) matches(character *KanjidicCharacter) bool {
+	if query.MinStrokes > 0 || query.MaxStrokes > 0 {
+		if !query.matchesStrokes(character) {
+			return false
+		}
+	}
+
+	if query.Radical > 0 {
+		if radical, err := character.ClassicalRadical(); err != nil || radical != query.Radical {
+			return false
+		}
+	}
+
+	if len(query.Grades) > 0 {
+		grade, ok, err := character.Grade()
+		if !ok || err != nil || !containsInt(query.Grades, grade) {
+			return false
+		}
+	}
+
+	if len(query.JLPT) > 0 {
+		level, ok, err := character.JLPT()
+		if !ok || err != nil || !containsInt(query.JLPT, level) {
+			return false
+		}
+	}
+
+	if query.MaxFrequency > 0 {
+		frequency, ok, err := character.Frequency()
+		if !ok || err != nil || frequency > query.MaxFrequency {
+			return false
+		}
+	}
+
+	if query.Meaning != "" && !hasMeaning(character, query.Meaning) {
+		return false
+	}
+
+	if query.Reference != "" && !hasReference(character, query.Reference, query.ReferenceNumber) {
+		return false
+	}
+
+	return true
+}
+
+func (query *KanjiQuery) matchesStrokes(character *KanjidicCharacter) bool {
+	counts, err := character.strokeCounts()
+	if err != nil || len(counts) == 0 {
+		return false
+	}
+
+	if !query.Miscounts {
+		counts = counts[:1]
+	}
+
+	for _, count := range counts {
+		if count >= query.MinStrokes && (query.MaxStrokes == 0 || count <= query.MaxStrokes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasMeaning reports whether all words of the query appear in the same
+// meaning, as the meaning index only tells that they appear in any.
+func hasMeaning(character *KanjidicCharacter, query string) bool {
+	if character.ReadingMeaning == nil {
+		return false
+	}
+
+	words := glossWords(query)
+	for _, group := range character.ReadingMeaning.Groups {
+		for _, meaning := range group.Meanings {
+			if meaningHasWords(meaning.Meaning, words) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func meaningHasWords(meaning string, words []string) bool {
+	found := make(map[string]bool)
+	for _, word := range glossWords(meaning) {
+		found[word] = true
+	}
+
+	for _, word := range words {
+		if !found[word] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasReference(character *KanjidicCharacter, kind, number string) bool {
+	for _, dr := range character.DictionaryNumbers {
+		if dr.Type == kind && (number == "" || dr.Value == number) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kanjiReadingKeys returns the forms under which a KANJIDIC reading is
+// indexed: the whole reading, and for kun readings with okurigana, the
+// stem alone.
+func kanjiReadingKeys(reading string) []string {
+	kun := ParseKunReading(reading)
+
+	keys := []string{katakanaToHiragana(kun.Reading())}
+	if kun.Okurigana != "" {
+		keys = append(keys, katakanaToHiragana(kun.Stem))
+	}
+
+	return keys
+}
+
+// normalizeKanjiReading converts a reading to the form used as index key.
+func normalizeKanjiReading(reading string) string {
+	return katakanaToHiragana(ParseKunReading(reading).Reading())
+}
@@ -0,0 +1,67 @@
+package jmdict
+
+import "testing"
+
+func testKanjiSearchIndex() *KanjiIndex {
+	eat := testCharacter("食", "ショク,た.べる,く.う", "eat,food")
+	eat.Radical = []KanjidicRadical{{Value: "184", Type: "classical"}}
+	eat.Misc.Grade, eat.Misc.JlptLevel, eat.Misc.Frequency = stringPointer("2"), stringPointer("4"), stringPointer("328")
+	eat.Misc.StrokeCounts = []string{"9"}
+	eat.DictionaryNumbers = []KanjidicDicNumber{{Value: "1472", Type: "heisig"}}
+
+	rice := testCharacter("飯", "ハン,めし", "meal,boiled rice")
+	rice.Radical = []KanjidicRadical{{Value: "184", Type: "classical"}}
+	rice.Misc.Grade, rice.Misc.Frequency = stringPointer("4"), stringPointer("1208")
+	rice.Misc.StrokeCounts = []string{"12", "11"}
+
+	devour := testCharacter("喰", "く.う,く.らう", "eat,drink")
+	devour.Radical = []KanjidicRadical{{Value: "30", Type: "classical"}}
+	devour.Misc.StrokeCounts = []string{"12"}
+
+	return NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{devour, rice, eat}})
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func TestKanjiIndexSearch(t *testing.T) {
+	idx := testKanjiSearchIndex()
+
+	tests := []struct {
+		name  string
+		query KanjiQuery
+		want  string
+	}{
+		{"all by frequency", KanjiQuery{}, "食飯喰"},
+		{"strokes", KanjiQuery{MinStrokes: 10, MaxStrokes: 11}, ""},
+		{"strokes with miscounts", KanjiQuery{MinStrokes: 10, MaxStrokes: 11, Miscounts: true}, "飯"},
+		{"minimum strokes", KanjiQuery{MinStrokes: 12}, "飯喰"},
+		{"radical", KanjiQuery{Radical: 184}, "食飯"},
+		{"grades", KanjiQuery{Grades: []int{1, 2, 3}}, "食"},
+		{"jlpt", KanjiQuery{JLPT: []int{4}}, "食"},
+		{"frequency", KanjiQuery{MaxFrequency: 1000}, "食"},
+		{"on reading in hiragana", KanjiQuery{Reading: "しょく"}, "食"},
+		{"kun reading stem", KanjiQuery{Reading: "く"}, "食喰"},
+		{"kun reading with okurigana", KanjiQuery{Reading: "くらう"}, "喰"},
+		{"kun reading in katakana", KanjiQuery{Reading: "メシ"}, "飯"},
+		{"meaning", KanjiQuery{Meaning: "Eat"}, "食喰"},
+		{"meaning words in one meaning", KanjiQuery{Meaning: "boiled rice"}, "飯"},
+		{"meaning words across meanings", KanjiQuery{Meaning: "eat food rice"}, ""},
+		{"reference", KanjiQuery{Reference: "heisig", ReferenceNumber: "1472"}, "食"},
+		{"combined", KanjiQuery{Reading: "く", Radical: 30}, "喰"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			for _, character := range idx.Search(test.query) {
+				got += character.Literal
+			}
+
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package jmdict
+
+import (
+	"sort"
+	"strings"
+)
+
+// KanjiWordIndex lists the JMdict words written with each kanji, with the
+// same concurrency guarantees as JmdictIndex.
+type KanjiWordIndex struct {
+	dict    *Jmdict
+	aligner aligner
+	words   map[rune][]int
+}
+
+// KanjiWord is a JMdict entry written with a given kanji.
+type KanjiWord struct {
+	Entry *JmdictEntry
+
+	// The kanji element containing the character, and its reading.
+	Expression string
+	Reading    string
+
+	// The KANJIDIC2 reading of the character used in the word (such as
+	// "た.べる" or "ショク") and its r_type ("ja_on", "ja_kun" or
+	// "nanori"), empty if the reading could not be determined.
+	KanjiReading     string
+	KanjiReadingType string
+}
+
+// NewKanjiWordIndex indexes the kanji of the expressions of the dictionary.
+// The kanji index is used to tell which reading of a kanji a word uses.
+func NewKanjiWordIndex(dict *Jmdict, kanji *KanjiIndex) *KanjiWordIndex {
+	idx := &KanjiWordIndex{
+		dict:    dict,
+		aligner: aligner{kanji: kanji},
+		words:   make(map[rune][]int),
+	}
+
+	for i := range dict.Entries {
+		for _, k := range dict.Entries[i].Kanji {
+			for _, c := range k.Expression {
+				if isKanji(c) {
+					idx.words[c] = appendUnique(idx.words[c], i)
+				}
+			}
+		}
+	}
+
+	return idx
+}
+
+// Words returns the entries with a kanji element containing the literal,
+// most common entries first.
+func (idx *KanjiWordIndex) Words(literal string) []*JmdictEntry {
+	c, ok := singleRune(literal)
+	if !ok {
+		return nil
+	}
+
+	entries := make([]*JmdictEntry, 0, len(idx.words[c]))
+	for _, i := range idx.words[c] {
+		entries = append(entries, &idx.dict.Entries[i])
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return jmdictPriorityScore(entries[i]) > jmdictPriorityScore(entries[j])
+	})
+
+	return entries
+}
+
+// TaggedWords returns the words of Words, with the reading of the literal
+// in each. An entry is listed once for each of its kanji elements which
+// contain the literal, using the first reading that applies to it.
+func (idx *KanjiWordIndex) TaggedWords(literal string) []KanjiWord {
+	var words []KanjiWord
+	for _, entry := range idx.Words(literal) {
+		for _, kanji := range entry.Kanji {
+			if !strings.Contains(kanji.Expression, literal) {
+				continue
+			}
+
+			word := KanjiWord{Entry: entry, Expression: kanji.Expression}
+			for _, reading := range entry.Readings {
+				if !readingApplies(&reading, kanji.Expression) {
+					continue
+				}
+
+				if word.Reading == "" {
+					word.Reading = reading.Reading
+				}
+
+				parts, ok := idx.aligner.align(kanji.Expression, reading.Reading)
+				if !ok {
+					continue
+				}
+
+				for _, part := range parts {
+					if part.text == literal {
+						word.Reading = reading.Reading
+						word.KanjiReading = part.source
+						word.KanjiReadingType = part.kind
+						break
+					}
+				}
+
+				if word.KanjiReading != "" {
+					break
+				}
+			}
+
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// readingApplies reports whether the reading element is a reading of the
+// kanji element, taking re_nokanji and re_restr into account.
+func readingApplies(reading *JmdictReading, expression string) bool {
+	if reading.NoKanji != nil {
+		return false
+	}
+
+	if len(reading.Restrictions) == 0 {
+		return true
+	}
+
+	for _, restriction := range reading.Restrictions {
+		if restriction == expression {
+			return true
+		}
+	}
+
+	return false
+}
+
+func singleRune(text string) (rune, bool) {
+	runes := []rune(text)
+	if len(runes) != 1 {
+		return 0, false
+	}
+
+	return runes[0], true
+}
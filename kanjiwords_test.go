@@ -0,0 +1,96 @@
+package jmdict
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKanjiWordIndex(t *testing.T) {
+	eatFood := testEntry(1, "食物", "しょくもつ,たべもの", testSense("n", "food"))
+	eat := testEntry(2, "食べる", "たべる", testSense("v1", "to eat"))
+	eat.Kanji[0].Priorities = []string{"ichi1"}
+	meal := testEntry(3, "食事", "しょくじ", testSense("n", "meal"))
+	school := testEntry(4, "学校", "がっこう", testSense("n", "school"))
+
+	dict := Jmdict{Entries: []JmdictEntry{eatFood, eat, meal, school}}
+	kanji := NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{
+		testCharacter("食", "ショク,た.べる,く.う", "eat"),
+		testCharacter("物", "ブツ,モツ,もの", "thing"),
+		testCharacter("事", "ジ,こと", "matter"),
+	}})
+	idx := NewKanjiWordIndex(&dict, kanji)
+
+	var words []int
+	for _, entry := range idx.Words("食") {
+		words = append(words, entry.Sequence)
+	}
+
+	if !reflect.DeepEqual(words, []int{2, 1, 3}) {
+		t.Errorf("Words(食) = %v, want the common word first", words)
+	}
+
+	if entries := idx.Words("食べ"); entries != nil {
+		t.Errorf("Words(食べ) = %v, want none for more than one character", entries)
+	}
+
+	var tagged []string
+	for _, word := range idx.TaggedWords("食") {
+		tagged = append(tagged, fmt.Sprintf("%s【%s】%s %s", word.Expression, word.Reading, word.KanjiReading, word.KanjiReadingType))
+	}
+
+	want := []string{
+		"食べる【たべる】た.べる ja_kun",
+		"食物【しょくもつ】ショク ja_on",
+		"食事【しょくじ】ショク ja_on",
+	}
+	if !reflect.DeepEqual(tagged, want) {
+		t.Errorf("TaggedWords(食) = %q, want %q", tagged, want)
+	}
+}
+
+func TestAlignKanjiReadings(t *testing.T) {
+	a := aligner{kanji: NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{
+		testCharacter("学", "ガク,まな.ぶ", "study"),
+		testCharacter("校", "コウ", "school"),
+		testCharacter("人", "ジン,ニン,ひと", "person"),
+		testCharacter("日", "ニチ,ジツ,ひ,-び,-か", "day"),
+		testCharacter("記", "キ,しる.す", "record"),
+	}})}
+
+	tests := []struct {
+		expression string
+		reading    string
+		want       string
+	}{
+		{"学校", "がっこう", "学:ガク 校:コウ"},
+		{"人々", "ひとびと", "人:ひと 々:ひと"},
+		{"日記", "にっき", "日:ニチ 記:キ"},
+		{"学ぶ", "まなぶ", "学:まな.ぶ ぶ"},
+		{"学校", "ひとびと", ""},
+	}
+
+	for _, test := range tests {
+		parts, ok := a.align(test.expression, test.reading)
+		if !ok {
+			if test.want != "" {
+				t.Errorf("align(%s, %s) failed", test.expression, test.reading)
+			}
+			continue
+		}
+
+		var got []string
+		for _, part := range parts {
+			if part.kana {
+				got = append(got, part.text)
+			} else {
+				got = append(got, part.text+":"+part.source)
+			}
+		}
+
+		if strings.Join(got, " ") != test.want {
+			t.Errorf("align(%s, %s) = %q, want %q", test.expression, test.reading, strings.Join(got, " "), test.want)
+		}
+	}
+}
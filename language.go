@@ -0,0 +1,92 @@
+package jmdict
+
+// KANJIDIC meanings are tagged with ISO 639-1 codes, while JMdict and
+// JMnedict use the ISO 639-2 bibliographic codes.
+var languageAlpha2 = map[string]string{
+	"dut": "nl",
+	"eng": "en",
+	"fre": "fr",
+	"ger": "de",
+	"hun": "hu",
+	"ita": "it",
+	"por": "pt",
+	"rus": "ru",
+	"slv": "sl",
+	"spa": "es",
+	"swe": "sv",
+}
+
+func languageOf(language *string, implied string) string {
+	if language == nil {
+		return implied
+	}
+
+	return *language
+}
+
+// filterJmdictLanguages removes the glosses in other languages, dropping
+// senses which no longer have any glosses. Senses without glosses to begin
+// with, such as pure cross-references, are kept. Returns false if the
+// entry has no senses left.
+func filterJmdictLanguages(entry *JmdictEntry, languages map[string]bool, implied *dictDefaults) bool {
+	senses := entry.Sense[:0]
+	for _, sense := range entry.Sense {
+		if len(sense.Glossary) == 0 {
+			senses = append(senses, sense)
+			continue
+		}
+
+		glossary := sense.Glossary[:0]
+		for _, gloss := range sense.Glossary {
+			if languages[languageOf(gloss.Language, implied.glossLanguage)] {
+				glossary = append(glossary, gloss)
+			}
+		}
+
+		if len(glossary) > 0 {
+			sense.Glossary = glossary
+			senses = append(senses, sense)
+		}
+	}
+
+	entry.Sense = senses
+	return len(senses) > 0
+}
+
+func filterJmnedictLanguages(entry *JmnedictEntry, languages map[string]bool, implied *dictDefaults) bool {
+	translations := entry.Translations[:0]
+	for _, trans := range entry.Translations {
+		if languages[languageOf(trans.Language, implied.translationLanguage)] {
+			translations = append(translations, trans)
+		}
+	}
+
+	entry.Translations = translations
+	return len(translations) > 0
+}
+
+// filterKanjidicLanguages removes the meanings in other languages. The
+// character itself is always kept, as its readings remain useful.
+func filterKanjidicLanguages(character *KanjidicCharacter, languages map[string]bool) {
+	rm := character.ReadingMeaning
+	if rm == nil {
+		return
+	}
+
+	for i := range rm.Groups {
+		group := &rm.Groups[i]
+
+		meanings := group.Meanings[:0]
+		for _, meaning := range group.Meanings {
+			language := languageOf(meaning.Language, "en")
+			for code := range languages {
+				if language == code || language == languageAlpha2[code] {
+					meanings = append(meanings, meaning)
+					break
+				}
+			}
+		}
+
+		group.Meanings = meanings
+	}
+}
@@ -0,0 +1,77 @@
+package jmdict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithLanguages(t *testing.T) {
+	tests := []struct {
+		languages []string
+		glosses   [][]string
+	}{
+		{[]string{"eng"}, [][]string{{"to eat"}, {"school"}}},
+		{[]string{"ger"}, [][]string{{"essen"}}},
+		{[]string{"eng", "ger"}, [][]string{{"to eat", "essen"}, {"school"}}},
+		{[]string{"fre"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.languages, ","), func(t *testing.T) {
+			var glosses [][]string
+			_, _, err := StreamJmdict(strings.NewReader(testJmdictXML), func(entry *JmdictEntry) error {
+				var contents []string
+				for _, sense := range entry.Sense {
+					for _, gloss := range sense.Glossary {
+						contents = append(contents, gloss.Content)
+					}
+				}
+				glosses = append(glosses, contents)
+				return nil
+			}, WithLanguages(test.languages...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(glosses, test.glosses) {
+				t.Errorf("glosses %v, want %v", glosses, test.glosses)
+			}
+		})
+	}
+}
+
+func TestFilterJmdictLanguagesKeepsReferences(t *testing.T) {
+	reference := JmdictSense{References: []string{"食う"}}
+	entry := testEntry(1, "食べる", "たべる", testSense("v1", "essen"), reference)
+	entry.Sense[0].Glossary[0].Language = impliedValue("ger")
+
+	defaults := newDictDefaults(&DTD{})
+	if !filterJmdictLanguages(&entry, map[string]bool{"eng": true}, defaults) {
+		t.Fatal("entry with a cross-reference sense was dropped")
+	}
+	if len(entry.Sense) != 1 || len(entry.Sense[0].References) != 1 {
+		t.Errorf("senses = %+v, want the cross-reference alone", entry.Sense)
+	}
+}
+
+func TestFilterKanjidicLanguages(t *testing.T) {
+	character := KanjidicCharacter{
+		Literal: "食",
+		ReadingMeaning: &KanjidicReadingMeaning{Groups: []KanjidicRMGroup{{Meanings: []KanjidicMeaning{
+			{Meaning: "eat"},
+			{Meaning: "manger", Language: impliedValue("fr")},
+			{Meaning: "comer", Language: impliedValue("es")},
+		}}}},
+	}
+
+	filterKanjidicLanguages(&character, map[string]bool{"eng": true, "fre": true})
+
+	var meanings []string
+	for _, meaning := range character.ReadingMeaning.Meanings() {
+		meanings = append(meanings, meaning.Meaning)
+	}
+	if want := []string{"eat", "manger"}; !reflect.DeepEqual(meanings, want) {
+		t.Errorf("meanings = %v, want %v", meanings, want)
+	}
+}
@@ -0,0 +1,166 @@
+package jmdict
+
+import "sort"
+
+// NameSplit is a way of splitting a Japanese full name into the family
+// name and the given name.
+type NameSplit struct {
+	Surname string `json:"surname"`
+	Given   string `json:"given"`
+
+	// The readings of each part, the one supported by the most entries
+	// first.
+	SurnameReadings []NameReading `json:"surnameReadings"`
+	GivenReadings   []NameReading `json:"givenReadings"`
+
+	// The share of the support of all splits of the name which goes to this
+	// split, between 0 and 1. The support of a split is the product of the
+	// number of surname entries matching the first part and of given name
+	// entries matching the second.
+	Confidence float64 `json:"confidence"`
+}
+
+// NameReading is a reading of a part of a name, with the number of JMnedict
+// entries giving the part that reading.
+type NameReading struct {
+	Reading string `json:"reading"`
+	Entries int    `json:"entries"`
+}
+
+// givenNameTypes are the name types of entries which support a given name.
+var givenNameTypes = []NameType{NameTypeGiven, NameTypeMale, NameTypeFemale}
+
+// SplitName splits a full name written without a space (山田太郎) into
+// family and given name, using the surname and given name entries of the
+// dictionary. Only splits for which both parts are known names are
+// returned, the most likely first; splits equally supported are ordered by
+// how evenly they divide the name. Each part may be written in kanji or
+// kana.
+func (idx *JmnedictIndex) SplitName(name string) []NameSplit {
+	runes := []rune(name)
+
+	var (
+		splits []NameSplit
+		scores []int
+		total  int
+	)
+
+	for i := 1; i < len(runes); i++ {
+		surname, given := string(runes[:i]), string(runes[i:])
+
+		surnameEntries := idx.nameEntries(surname, NameTypeSurname)
+		givenEntries := idx.nameEntries(given, givenNameTypes...)
+		if len(surnameEntries) == 0 || len(givenEntries) == 0 {
+			continue
+		}
+
+		score := len(surnameEntries) * len(givenEntries)
+		splits = append(splits, NameSplit{
+			Surname:         surname,
+			Given:           given,
+			SurnameReadings: idx.nameReadings(surname, surnameEntries),
+			GivenReadings:   idx.nameReadings(given, givenEntries),
+		})
+		scores = append(scores, score)
+		total += score
+	}
+
+	for i := range splits {
+		splits[i].Confidence = float64(scores[i]) / float64(total)
+	}
+
+	sort.SliceStable(splits, func(i, j int) bool {
+		if splits[i].Confidence != splits[j].Confidence {
+			return splits[i].Confidence > splits[j].Confidence
+		}
+		return nameImbalance(&splits[i]) < nameImbalance(&splits[j])
+	})
+
+	return splits
+}
+
+// nameEntries returns the indices of the entries of any of the types with a
+// kanji or reading element matching the part.
+func (idx *JmnedictIndex) nameEntries(part string, types ...NameType) []int {
+	matches := mergeIndices(idx.expressions[part], idx.readings[part])
+
+	var indices []int
+	for _, t := range types {
+		indices = mergeIndices(indices, intersectIndices(matches, idx.types[t]))
+	}
+
+	return indices
+}
+
+// nameReadings ranks the readings the entries give the part by the number
+// of entries giving each. A part written in kana is its own reading.
+func (idx *JmnedictIndex) nameReadings(part string, indices []int) []NameReading {
+	counts := make(map[string]int)
+	var readings []string
+	add := func(reading string) {
+		if counts[reading] == 0 {
+			readings = append(readings, reading)
+		}
+		counts[reading]++
+	}
+
+	for _, i := range indices {
+		entry := &idx.dic.Entries[i]
+		if !hasExpression(entry, part) {
+			add(part)
+			continue
+		}
+
+		for _, reading := range entry.Readings {
+			if restrictionsAllow(reading.Restrictions, part) {
+				add(reading.Reading)
+			}
+		}
+	}
+
+	result := make([]NameReading, 0, len(readings))
+	for _, reading := range readings {
+		result = append(result, NameReading{Reading: reading, Entries: counts[reading]})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Entries > result[j].Entries
+	})
+
+	return result
+}
+
+// restrictionsAllow reports whether a reading with the re_restr elements
+// applies to the expression.
+func restrictionsAllow(restrictions []string, expression string) bool {
+	if len(restrictions) == 0 {
+		return true
+	}
+
+	for _, restriction := range restrictions {
+		if restriction == expression {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasExpression(entry *JmnedictEntry, expression string) bool {
+	for _, kanji := range entry.Kanji {
+		if kanji.Expression == expression {
+			return true
+		}
+	}
+
+	return false
+}
+
+func nameImbalance(split *NameSplit) int {
+	d := len([]rune(split.Surname)) - len([]rune(split.Given))
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
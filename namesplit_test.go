@@ -0,0 +1,40 @@
+package jmdict
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitName(t *testing.T) {
+	idx := NewJmnedictIndex(&Jmnedict{Entries: []JmnedictEntry{
+		testName(1, "山田", "やまだ", "surname"),
+		testName(2, "山田", "さんだ", "surname"),
+		testName(3, "山田", "やまだ", "place"),
+		testName(4, "山", "やま", "surname"),
+		testName(5, "太郎", "たろう", "masc"),
+		testName(6, "田太郎", "たたろう", "given"),
+		testName(7, "", "はなこ", "fem"),
+	}})
+
+	var got []string
+	for _, split := range idx.SplitName("山田太郎") {
+		got = append(got, fmt.Sprintf("%s/%s %.2f %v %v", split.Surname, split.Given, split.Confidence, split.SurnameReadings, split.GivenReadings))
+	}
+
+	want := []string{
+		"山田/太郎 0.67 [{やまだ 1} {さんだ 1}] [{たろう 1}]",
+		"山/田太郎 0.33 [{やま 1}] [{たたろう 1}]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if splits := idx.SplitName("山田はなこ"); len(splits) != 1 || splits[0].Given != "はなこ" || splits[0].GivenReadings[0].Reading != "はなこ" {
+		t.Errorf("SplitName(山田はなこ) = %+v", splits)
+	}
+
+	if splits := idx.SplitName("田中一郎"); len(splits) != 0 {
+		t.Errorf("SplitName(田中一郎) = %+v, want no splits", splits)
+	}
+}
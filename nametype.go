@@ -0,0 +1,154 @@
+package jmdict
+
+import "strings"
+
+// NameType is a JMnedict name_type entity code, classifying the names of an
+// entry.
+type NameType string
+
+const (
+	NameTypeCharacter    NameType = "char"
+	NameTypeCompany      NameType = "company"
+	NameTypeCreature     NameType = "creat"
+	NameTypeDeity        NameType = "dei"
+	NameTypeDocument     NameType = "doc"
+	NameTypeEvent        NameType = "ev"
+	NameTypeFemale       NameType = "fem"
+	NameTypeFiction      NameType = "fict"
+	NameTypeGiven        NameType = "given"
+	NameTypeGroup        NameType = "group"
+	NameTypeLegend       NameType = "leg"
+	NameTypeMale         NameType = "masc"
+	NameTypeMythology    NameType = "myth"
+	NameTypeObject       NameType = "obj"
+	NameTypeOrganization NameType = "organization"
+	NameTypeOther        NameType = "oth"
+	NameTypePerson       NameType = "person"
+	NameTypePlace        NameType = "place"
+	NameTypeProduct      NameType = "product"
+	NameTypeReligion     NameType = "relig"
+	NameTypeService      NameType = "serv"
+	NameTypeShip         NameType = "ship"
+	NameTypeStation      NameType = "station"
+	NameTypeSurname      NameType = "surname"
+	NameTypeUnclassified NameType = "unclass"
+	NameTypeWork         NameType = "work"
+)
+
+// nameTypeDescriptions holds the replacement text of the name_type entities
+// as declared in the JMnedict DTD.
+var nameTypeDescriptions = map[NameType]string{
+	NameTypeCharacter:    "character",
+	NameTypeCompany:      "company name",
+	NameTypeCreature:     "creature",
+	NameTypeDeity:        "deity",
+	NameTypeDocument:     "document",
+	NameTypeEvent:        "event",
+	NameTypeFemale:       "female given name or forename",
+	NameTypeFiction:      "fiction",
+	NameTypeGiven:        "given name or forename, gender not specified",
+	NameTypeGroup:        "group",
+	NameTypeLegend:       "legend",
+	NameTypeMale:         "male given name or forename",
+	NameTypeMythology:    "mythology",
+	NameTypeObject:       "object",
+	NameTypeOrganization: "organization name",
+	NameTypeOther:        "other",
+	NameTypePerson:       "full name of a particular person",
+	NameTypePlace:        "place name",
+	NameTypeProduct:      "product name",
+	NameTypeReligion:     "religion",
+	NameTypeService:      "service",
+	NameTypeShip:         "ship name",
+	NameTypeStation:      "railway station",
+	NameTypeSurname:      "family or surname",
+	NameTypeUnclassified: "unclassified name",
+	NameTypeWork:         "work of art, literature, music, etc. name",
+}
+
+// Description returns the description of the name type from the JMnedict
+// DTD, or the empty string for unknown codes.
+func (t NameType) Description() string {
+	return nameTypeDescriptions[t]
+}
+
+// ParseNameType converts an entity code or its description to a name type.
+// Descriptions are what the name types hold when the dictionary was loaded
+// with entities expanded.
+func ParseNameType(value string) (NameType, bool) {
+	if _, ok := nameTypeDescriptions[NameType(value)]; ok {
+		return NameType(value), true
+	}
+
+	for t, description := range nameTypeDescriptions {
+		if strings.EqualFold(value, description) {
+			return t, true
+		}
+	}
+
+	return "", false
+}
+
+// NameTypes returns the distinct name types of the translations of the
+// entry, in order of appearance. Unknown codes are skipped.
+func (entry *JmnedictEntry) NameTypes() []NameType {
+	var types []NameType
+	for _, trans := range entry.Translations {
+		for _, value := range trans.NameTypes {
+			if t, ok := ParseNameType(value); ok && !containsNameType(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+
+	return types
+}
+
+// HasNameType reports whether any translation of the entry is of the type.
+func (entry *JmnedictEntry) HasNameType(t NameType) bool {
+	return containsNameType(entry.NameTypes(), t)
+}
+
+func containsNameType(types []NameType, t NameType) bool {
+	for _, u := range types {
+		if u == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LookupNameType returns the entries with a translation of the type.
+func (idx *JmnedictIndex) LookupNameType(t NameType) []*JmnedictEntry {
+	return idx.entries(idx.types[t])
+}
+
+// LookupReadingOfType returns the entries of the type with a reading
+// element matching the reading exactly, such as the place names read
+// かわさき.
+func (idx *JmnedictIndex) LookupReadingOfType(reading string, t NameType) []*JmnedictEntry {
+	return idx.entries(intersectIndices(idx.readings[reading], idx.types[t]))
+}
+
+// LookupExpressionOfType returns the entries of the type with a kanji
+// element matching the expression exactly.
+func (idx *JmnedictIndex) LookupExpressionOfType(expression string, t NameType) []*JmnedictEntry {
+	return idx.entries(intersectIndices(idx.expressions[expression], idx.types[t]))
+}
+
+// LookupCharacterOfType returns the entries of the type with a kanji
+// element containing the character, such as the surnames written with 田.
+func (idx *JmnedictIndex) LookupCharacterOfType(character string, t NameType) []*JmnedictEntry {
+	var indices []int
+	for _, i := range idx.types[t] {
+		for _, kanji := range idx.dic.Entries[i].Kanji {
+			if strings.Contains(kanji.Expression, character) {
+				indices = append(indices, i)
+				break
+			}
+		}
+	}
+
+	return idx.entries(indices)
+}
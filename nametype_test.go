@@ -0,0 +1,74 @@
+package jmdict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNameType(t *testing.T) {
+	tests := []struct {
+		value string
+		want  NameType
+		ok    bool
+	}{
+		{"place", NameTypePlace, true},
+		{"family or surname", NameTypeSurname, true},
+		{"Place Name", NameTypePlace, true},
+		{"river", "", false},
+	}
+
+	for _, test := range tests {
+		if got, ok := ParseNameType(test.value); got != test.want || ok != test.ok {
+			t.Errorf("ParseNameType(%q) = %q, %v, want %q, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+
+	if got := NameTypePlace.Description(); got != "place name" {
+		t.Errorf("Description() = %q", got)
+	}
+}
+
+func TestJmnedictEntryNameTypes(t *testing.T) {
+	entry := testName(1, "川崎", "かわさき", "place", "unknown", "surname")
+	entry.Translations = append(entry.Translations, JmnedictTranslation{NameTypes: []string{"place name"}})
+
+	if got := entry.NameTypes(); !reflect.DeepEqual(got, []NameType{NameTypePlace, NameTypeSurname}) {
+		t.Errorf("NameTypes() = %v", got)
+	}
+
+	if !entry.HasNameType(NameTypeSurname) || entry.HasNameType(NameTypeGiven) {
+		t.Error("HasNameType gives the wrong answer")
+	}
+}
+
+func TestJmnedictIndexNameTypes(t *testing.T) {
+	idx := NewJmnedictIndex(&Jmnedict{Entries: []JmnedictEntry{
+		testName(1, "川崎", "かわさき", "place"),
+		testName(2, "川崎", "かわさき", "surname"),
+		testName(3, "田中", "たなか", "surname"),
+		testName(4, "山田", "やまだ", "place", "surname"),
+	}})
+
+	tests := []struct {
+		name string
+		got  []*JmnedictEntry
+		want []int
+	}{
+		{"type", idx.LookupNameType(NameTypePlace), []int{1, 4}},
+		{"reading", idx.LookupReadingOfType("かわさき", NameTypePlace), []int{1}},
+		{"expression", idx.LookupExpressionOfType("川崎", NameTypeSurname), []int{2}},
+		{"character", idx.LookupCharacterOfType("田", NameTypeSurname), []int{3, 4}},
+		{"none", idx.LookupCharacterOfType("田", NameTypeCompany), nil},
+	}
+
+	for _, test := range tests {
+		var got []int
+		for _, entry := range test.got {
+			got = append(got, entry.Sequence)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
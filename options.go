@@ -0,0 +1,197 @@
+package jmdict
+
+import "sort"
+
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	defaults  bool
+	languages map[string]bool
+
+	jmdictFilter   Predicate[JmdictEntry]
+	jmnedictFilter Predicate[JmnedictEntry]
+	kanjidicFilter Predicate[KanjidicCharacter]
+
+	// Implied attribute values, resolved from the DTD of the file once
+	// the first element has been read.
+	implied *dictDefaults
+}
+
+func newLoadOptions(options []LoadOption) loadOptions {
+	var opts loadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return opts
+}
+
+// WithDefaults fills in the values implied by the DTD for attributes that
+// are absent from the file: the language of glosses, loanword sources and
+// name translations ("eng") and the type of loanword sources ("full").
+// Populated values are marked as implied on the containing element.
+func WithDefaults() LoadOption {
+	return func(opts *loadOptions) {
+		opts.defaults = true
+	}
+}
+
+// WithLanguages keeps only the glosses, name translations and kanji
+// meanings in the given languages, identified by their ISO 639-2
+// bibliographic codes (e.g. "eng", "ger", "fre"). Senses and name entries
+// left without any translation are dropped. Filtering is applied as each
+// entry is decoded, so discarded content is never retained.
+func WithLanguages(languages ...string) LoadOption {
+	return func(opts *loadOptions) {
+		if opts.languages == nil {
+			opts.languages = make(map[string]bool)
+		}
+
+		for _, language := range languages {
+			opts.languages[language] = true
+		}
+	}
+}
+
+// languageList returns the languages of WithLanguages in order, to be
+// recorded in the DictionaryInfo.
+func (opts *loadOptions) languageList() []string {
+	if opts.languages == nil {
+		return nil
+	}
+
+	languages := make([]string, 0, len(opts.languages))
+	for language := range opts.languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+// WithJmdictFilter only keeps the JMdict entries matching the predicate,
+// which is evaluated after the other load options have been applied.
+// Multiple filters must all match. The option may be reused, and applied
+// concurrently.
+func WithJmdictFilter(predicate Predicate[JmdictEntry]) LoadOption {
+	return func(opts *loadOptions) {
+		p := predicate
+		if opts.jmdictFilter != nil {
+			p = And(opts.jmdictFilter, p)
+		}
+		opts.jmdictFilter = p
+	}
+}
+
+// WithJmnedictFilter only keeps the JMnedict entries matching the
+// predicate, like WithJmdictFilter.
+func WithJmnedictFilter(predicate Predicate[JmnedictEntry]) LoadOption {
+	return func(opts *loadOptions) {
+		p := predicate
+		if opts.jmnedictFilter != nil {
+			p = And(opts.jmnedictFilter, p)
+		}
+		opts.jmnedictFilter = p
+	}
+}
+
+// WithKanjidicFilter only keeps the KANJIDIC2 characters matching the
+// predicate, like WithJmdictFilter.
+func WithKanjidicFilter(predicate Predicate[KanjidicCharacter]) LoadOption {
+	return func(opts *loadOptions) {
+		p := predicate
+		if opts.kanjidicFilter != nil {
+			p = And(opts.kanjidicFilter, p)
+		}
+		opts.kanjidicFilter = p
+	}
+}
+
+// ApplyJmdictOptions applies load options to an already loaded dictionary,
+// such as one read from a snapshot. The entries of the dictionary are
+// modified in place.
+func ApplyJmdictOptions(dict Jmdict, options ...LoadOption) Jmdict {
+	opts := newLoadOptions(options)
+
+	entries := dict.Entries[:0]
+	for i := range dict.Entries {
+		if opts.processJmdict(&dict.Entries[i], &dict.Info) {
+			entries = append(entries, dict.Entries[i])
+		}
+	}
+
+	dict.Entries = entries
+	return dict
+}
+
+func ApplyJmnedictOptions(dic Jmnedict, options ...LoadOption) Jmnedict {
+	opts := newLoadOptions(options)
+
+	entries := dic.Entries[:0]
+	for i := range dic.Entries {
+		if opts.processJmnedict(&dic.Entries[i], &dic.Info) {
+			entries = append(entries, dic.Entries[i])
+		}
+	}
+
+	dic.Entries = entries
+	return dic
+}
+
+func ApplyKanjidicOptions(dic Kanjidic, options ...LoadOption) Kanjidic {
+	opts := newLoadOptions(options)
+
+	characters := dic.Characters[:0]
+	for i := range dic.Characters {
+		if opts.processKanjidic(&dic.Characters[i], &dic.Info) {
+			characters = append(characters, dic.Characters[i])
+		}
+	}
+
+	dic.Characters = characters
+	return dic
+}
+
+func (opts *loadOptions) resolveImplied(info *DictionaryInfo) *dictDefaults {
+	if opts.implied == nil {
+		opts.implied = newDictDefaults(&info.DTD)
+	}
+
+	return opts.implied
+}
+
+// processJmdict applies the load options to a decoded entry, returning
+// false if the entry should be discarded.
+func (opts *loadOptions) processJmdict(entry *JmdictEntry, info *DictionaryInfo) bool {
+	implied := opts.resolveImplied(info)
+	if opts.defaults {
+		implied.applyJmdict(entry)
+	}
+
+	if opts.languages != nil && !filterJmdictLanguages(entry, opts.languages, implied) {
+		return false
+	}
+
+	return opts.jmdictFilter == nil || opts.jmdictFilter(entry)
+}
+
+func (opts *loadOptions) processJmnedict(entry *JmnedictEntry, info *DictionaryInfo) bool {
+	implied := opts.resolveImplied(info)
+	if opts.defaults {
+		implied.applyJmnedict(entry)
+	}
+
+	if opts.languages != nil && !filterJmnedictLanguages(entry, opts.languages, implied) {
+		return false
+	}
+
+	return opts.jmnedictFilter == nil || opts.jmnedictFilter(entry)
+}
+
+func (opts *loadOptions) processKanjidic(character *KanjidicCharacter, info *DictionaryInfo) bool {
+	if opts.languages != nil {
+		filterKanjidicLanguages(character, opts.languages)
+	}
+
+	return opts.kanjidicFilter == nil || opts.kanjidicFilter(character)
+}
@@ -0,0 +1,341 @@
+package jmdict
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrRevisionMismatch = errors.New("dictionary revision does not match patch")
+
+type PatchOperation string
+
+const (
+	PatchInsert PatchOperation = "insert"
+	PatchUpdate PatchOperation = "update"
+	PatchDelete PatchOperation = "delete"
+)
+
+type JmdictPatch struct {
+	// The revision of the dictionary the patch applies to, and the revision
+	// it is expected to produce, as computed by JmdictRevision.
+	Base   string `json:"base"`
+	Target string `json:"target"`
+
+	Operations []JmdictPatchOperation `json:"operations"`
+}
+
+type JmdictPatchOperation struct {
+	Operation PatchOperation `json:"op"`
+	Sequence  int            `json:"sequence"`
+
+	// The complete new entry for insert and update operations.
+	Entry *JmdictEntry `json:"entry,omitempty"`
+}
+
+type JmnedictPatch struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+
+	Operations []JmnedictPatchOperation `json:"operations"`
+}
+
+type JmnedictPatchOperation struct {
+	Operation PatchOperation `json:"op"`
+	Sequence  int            `json:"sequence"`
+	Entry     *JmnedictEntry `json:"entry,omitempty"`
+}
+
+type KanjidicPatch struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+
+	Operations []KanjidicPatchOperation `json:"operations"`
+}
+
+type KanjidicPatchOperation struct {
+	Operation PatchOperation     `json:"op"`
+	Literal   string             `json:"literal"`
+	Character *KanjidicCharacter `json:"character,omitempty"`
+}
+
+// JmdictRevision returns a digest identifying the content of the
+// dictionary entries, used to check that a patch is applied to the
+// release it was generated from.
+func JmdictRevision(dict Jmdict) string {
+	return patchRevision(dict.Entries, func(e *JmdictEntry) int { return e.Sequence }, (*xmlWriter).jmdictEntry)
+}
+
+func JmnedictRevision(dic Jmnedict) string {
+	return patchRevision(dic.Entries, func(e *JmnedictEntry) int { return e.Sequence }, (*xmlWriter).jmnedictEntry)
+}
+
+func KanjidicRevision(dic Kanjidic) string {
+	return patchRevision(dic.Characters, func(c *KanjidicCharacter) string { return c.Literal }, (*xmlWriter).kanjidicCharacter)
+}
+
+func NewJmdictPatch(old, new Jmdict) JmdictPatch {
+	key := func(e *JmdictEntry) int { return e.Sequence }
+
+	patch := JmdictPatch{Base: JmdictRevision(old), Target: JmdictRevision(new)}
+	for _, step := range patchSteps(old.Entries, new.Entries, key, (*xmlWriter).jmdictEntry) {
+		patch.Operations = append(patch.Operations, JmdictPatchOperation{
+			Operation: step.operation,
+			Sequence:  step.key,
+			Entry:     step.value,
+		})
+	}
+
+	return patch
+}
+
+func NewJmnedictPatch(old, new Jmnedict) JmnedictPatch {
+	key := func(e *JmnedictEntry) int { return e.Sequence }
+
+	patch := JmnedictPatch{Base: JmnedictRevision(old), Target: JmnedictRevision(new)}
+	for _, step := range patchSteps(old.Entries, new.Entries, key, (*xmlWriter).jmnedictEntry) {
+		patch.Operations = append(patch.Operations, JmnedictPatchOperation{
+			Operation: step.operation,
+			Sequence:  step.key,
+			Entry:     step.value,
+		})
+	}
+
+	return patch
+}
+
+func NewKanjidicPatch(old, new Kanjidic) KanjidicPatch {
+	key := func(c *KanjidicCharacter) string { return c.Literal }
+
+	patch := KanjidicPatch{Base: KanjidicRevision(old), Target: KanjidicRevision(new)}
+	for _, step := range patchSteps(old.Characters, new.Characters, key, (*xmlWriter).kanjidicCharacter) {
+		patch.Operations = append(patch.Operations, KanjidicPatchOperation{
+			Operation: step.operation,
+			Literal:   step.key,
+			Character: step.value,
+		})
+	}
+
+	return patch
+}
+
+// ApplyJmdictPatch updates the dictionary in place. The dictionary is left
+// untouched if its revision does not match the base revision of the patch,
+// if an operation refers to a missing (or, for inserts, existing) entry, or
+// if the result does not match the target revision.
+func ApplyJmdictPatch(dict *Jmdict, patch JmdictPatch) error {
+	var steps []patchStep[int, JmdictEntry]
+	for _, op := range patch.Operations {
+		steps = append(steps, patchStep[int, JmdictEntry]{op.Operation, op.Sequence, op.Entry})
+	}
+
+	key := func(e *JmdictEntry) int { return e.Sequence }
+	entries, err := applyPatchSteps(dict.Entries, steps, key, (*xmlWriter).jmdictEntry, patch.Base, patch.Target)
+	if err != nil {
+		return err
+	}
+
+	dict.Entries = entries
+	return nil
+}
+
+func ApplyJmnedictPatch(dic *Jmnedict, patch JmnedictPatch) error {
+	var steps []patchStep[int, JmnedictEntry]
+	for _, op := range patch.Operations {
+		steps = append(steps, patchStep[int, JmnedictEntry]{op.Operation, op.Sequence, op.Entry})
+	}
+
+	key := func(e *JmnedictEntry) int { return e.Sequence }
+	entries, err := applyPatchSteps(dic.Entries, steps, key, (*xmlWriter).jmnedictEntry, patch.Base, patch.Target)
+	if err != nil {
+		return err
+	}
+
+	dic.Entries = entries
+	return nil
+}
+
+func ApplyKanjidicPatch(dic *Kanjidic, patch KanjidicPatch) error {
+	var steps []patchStep[string, KanjidicCharacter]
+	for _, op := range patch.Operations {
+		steps = append(steps, patchStep[string, KanjidicCharacter]{op.Operation, op.Literal, op.Character})
+	}
+
+	key := func(c *KanjidicCharacter) string { return c.Literal }
+	characters, err := applyPatchSteps(dic.Characters, steps, key, (*xmlWriter).kanjidicCharacter, patch.Base, patch.Target)
+	if err != nil {
+		return err
+	}
+
+	dic.Characters = characters
+	return nil
+}
+
+type patchKey interface {
+	~int | ~string
+}
+
+type patchStep[K patchKey, E any] struct {
+	operation PatchOperation
+	key       K
+	value     *E
+}
+
+// patchRevision hashes values in key order, so that the revision does not
+// depend on where inserted values were placed. Values are hashed in their
+// XML form, with coded fields as plain text, so that the revision depends
+// on the content of the dictionary rather than on the layout of the Go
+// types holding it.
+func patchRevision[K patchKey, E any](values []E, key func(*E) K, write func(*xmlWriter, *E)) string {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return key(&values[order[i]]) < key(&values[order[j]]) })
+
+	hash := sha256.New()
+	x := newXMLWriter(hash, &DictionaryInfo{})
+	for _, i := range order {
+		write(x, &values[i])
+	}
+
+	// Writes to a hash never return an error.
+	x.flush()
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// patchSteps compares the values in their XML form, like patchRevision,
+// so that values differing only in fields which are not written (such as
+// the flags recording implied attribute values) are not updated.
+func patchSteps[K patchKey, E any](old, new []E, key func(*E) K, write func(*xmlWriter, *E)) []patchStep[K, E] {
+	oldValues := make(map[K]*E)
+	for i := range old {
+		oldValues[key(&old[i])] = &old[i]
+	}
+
+	newKeys := make(map[K]bool)
+	var steps []patchStep[K, E]
+	for i := range new {
+		value := &new[i]
+		k := key(value)
+		newKeys[k] = true
+
+		if o, ok := oldValues[k]; !ok {
+			steps = append(steps, patchStep[K, E]{PatchInsert, k, value})
+		} else if patchXML(o, write) != patchXML(value, write) {
+			steps = append(steps, patchStep[K, E]{PatchUpdate, k, value})
+		}
+	}
+
+	for k := range oldValues {
+		if !newKeys[k] {
+			steps = append(steps, patchStep[K, E]{PatchDelete, k, nil})
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].key < steps[j].key })
+	return steps
+}
+
+func patchXML[E any](value *E, write func(*xmlWriter, *E)) string {
+	var buffer bytes.Buffer
+	x := newXMLWriter(&buffer, &DictionaryInfo{})
+	write(x, value)
+
+	// Writes to a buffer never return an error.
+	x.flush()
+
+	return buffer.String()
+}
+
+// applyPatchSteps checks the steps against the values, then builds the
+// result in a single pass. When the values are ordered by key, as the
+// entries of the published JMdict and JMnedict files are, the inserted
+// values are merged in by key so that the result stays ordered. Otherwise
+// (the characters of KANJIDIC2 are in JIS order) they are appended at the
+// end, as there is no position they belong at.
+func applyPatchSteps[K patchKey, E any](values []E, steps []patchStep[K, E], key func(*E) K, write func(*xmlWriter, *E), base, target string) ([]E, error) {
+	if revision := patchRevision(values, key, write); revision != base {
+		return nil, fmt.Errorf("%w: expected base %s, found %s", ErrRevisionMismatch, base, revision)
+	}
+
+	exists := make(map[K]bool, len(values))
+	for i := range values {
+		exists[key(&values[i])] = true
+	}
+
+	var (
+		updates = make(map[K]*E)
+		deletes = make(map[K]bool)
+		inserts []*E
+		seen    = make(map[K]bool)
+	)
+
+	for _, step := range steps {
+		if seen[step.key] {
+			return nil, fmt.Errorf("cannot %s %v: more than one operation", step.operation, step.key)
+		}
+		seen[step.key] = true
+
+		switch step.operation {
+		case PatchInsert:
+			if exists[step.key] {
+				return nil, fmt.Errorf("cannot insert %v: already exists", step.key)
+			}
+			if step.value == nil || key(step.value) != step.key {
+				return nil, fmt.Errorf("cannot insert %v: missing or mismatched value", step.key)
+			}
+			inserts = append(inserts, step.value)
+		case PatchUpdate:
+			if !exists[step.key] {
+				return nil, fmt.Errorf("cannot update %v: not found", step.key)
+			}
+			if step.value == nil || key(step.value) != step.key {
+				return nil, fmt.Errorf("cannot update %v: missing or mismatched value", step.key)
+			}
+			updates[step.key] = step.value
+		case PatchDelete:
+			if !exists[step.key] {
+				return nil, fmt.Errorf("cannot delete %v: not found", step.key)
+			}
+			deletes[step.key] = true
+		default:
+			return nil, fmt.Errorf("unknown patch operation %q", step.operation)
+		}
+	}
+
+	sort.SliceStable(inserts, func(i, j int) bool { return key(inserts[i]) < key(inserts[j]) })
+	ordered := sort.SliceIsSorted(values, func(i, j int) bool { return key(&values[i]) < key(&values[j]) })
+
+	result := make([]E, 0, len(values)-len(deletes)+len(inserts))
+	for i := range values {
+		k := key(&values[i])
+		for ordered && len(inserts) > 0 && key(inserts[0]) < k {
+			result, inserts = append(result, *inserts[0]), inserts[1:]
+		}
+
+		switch {
+		case deletes[k]:
+		case updates[k] != nil:
+			result = append(result, *updates[k])
+		default:
+			result = append(result, values[i])
+		}
+	}
+
+	for _, value := range inserts {
+		result = append(result, *value)
+	}
+
+	if target != "" {
+		if revision := patchRevision(result, key, write); revision != target {
+			return nil, fmt.Errorf("%w: expected target %s, produced %s", ErrRevisionMismatch, target, revision)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package jmdict
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJmdictPatchRoundTrip(t *testing.T) {
+	base := Jmdict{Entries: []JmdictEntry{
+		testEntry(1, "私", "わたし", testSense("pn")),
+		testEntry(2, "学校", "がっこう", testSense("n")),
+		testEntry(4, "行く", "いく", testSense("v5k-s")),
+	}}
+
+	updated := testEntry(2, "学校", "がっこう", testSense("n", "school"))
+
+	tests := []struct {
+		name    string
+		entries []JmdictEntry
+		ops     []PatchOperation
+	}{
+		{"unchanged", base.Entries, nil},
+		{"insert", []JmdictEntry{base.Entries[0], base.Entries[1], testEntry(3, "", "に", testSense("prt")), base.Entries[2]}, []PatchOperation{PatchInsert}},
+		{"insert first and last", []JmdictEntry{testEntry(0, "", "は", testSense("prt")), base.Entries[0], base.Entries[1], base.Entries[2], testEntry(5, "来る", "くる", testSense("vk"))}, []PatchOperation{PatchInsert, PatchInsert}},
+		{"update", []JmdictEntry{base.Entries[0], updated, base.Entries[2]}, []PatchOperation{PatchUpdate}},
+		{"delete", []JmdictEntry{base.Entries[0], base.Entries[2]}, []PatchOperation{PatchDelete}},
+		{"delete all", nil, []PatchOperation{PatchDelete, PatchDelete, PatchDelete}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := Jmdict{Entries: test.entries}
+			patch := NewJmdictPatch(base, target)
+
+			var ops []PatchOperation
+			for _, op := range patch.Operations {
+				ops = append(ops, op.Operation)
+			}
+			if !reflect.DeepEqual(ops, test.ops) {
+				t.Errorf("operations = %v, want %v", ops, test.ops)
+			}
+
+			dict := Jmdict{Entries: append([]JmdictEntry(nil), base.Entries...)}
+			if err := ApplyJmdictPatch(&dict, patch); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := JmdictRevision(dict), JmdictRevision(target); got != want {
+				t.Errorf("revision = %s, want %s", got, want)
+			}
+			if len(dict.Entries) != len(test.entries) {
+				t.Fatalf("got %d entries, want %d", len(dict.Entries), len(test.entries))
+			}
+			for i := range dict.Entries {
+				if !reflect.DeepEqual(dict.Entries[i], test.entries[i]) {
+					t.Errorf("entry %d = %+v, want %+v", i, dict.Entries[i], test.entries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJmdictPatchRevisionMismatch(t *testing.T) {
+	base := Jmdict{Entries: []JmdictEntry{testEntry(1, "私", "わたし", testSense("pn"))}}
+	target := Jmdict{Entries: []JmdictEntry{testEntry(1, "私", "わたくし", testSense("pn"))}}
+	patch := NewJmdictPatch(base, target)
+
+	other := Jmdict{Entries: []JmdictEntry{testEntry(1, "僕", "ぼく", testSense("pn"))}}
+	if err := ApplyJmdictPatch(&other, patch); !errors.Is(err, ErrRevisionMismatch) {
+		t.Fatalf("got error %v, want %v", err, ErrRevisionMismatch)
+	}
+
+	if other.Entries[0].Kanji[0].Expression != "僕" {
+		t.Errorf("dictionary was modified by a failed patch: %+v", other.Entries)
+	}
+
+	// A patch applies once: the patched dictionary is at its target, not
+	// its base.
+	if err := ApplyJmdictPatch(&base, patch); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyJmdictPatch(&base, patch); !errors.Is(err, ErrRevisionMismatch) {
+		t.Errorf("reapplying the patch: got error %v, want %v", err, ErrRevisionMismatch)
+	}
+}
+
+func TestJmdictRevisionIgnoresImpliedValues(t *testing.T) {
+	plain, _, err := LoadJmdict(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaulted, _, err := LoadJmdict(strings.NewReader(testJmdictXML), WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if JmdictRevision(plain) != JmdictRevision(defaulted) {
+		t.Error("revisions differ between loads with and without WithDefaults")
+	}
+
+	if patch := NewJmdictPatch(plain, defaulted); len(patch.Operations) != 0 {
+		t.Errorf("patch between loads with and without WithDefaults = %+v, want no operations", patch.Operations)
+	}
+
+	reordered := Jmdict{Entries: []JmdictEntry{plain.Entries[1], plain.Entries[0]}}
+	if JmdictRevision(plain) != JmdictRevision(reordered) {
+		t.Error("revision depends on the order of the entries")
+	}
+
+	plain.Entries[0].Sense[0].Glossary[0].Content = "to consume"
+	if JmdictRevision(plain) == JmdictRevision(defaulted) {
+		t.Error("revision did not change with a gloss")
+	}
+}
+
+func TestJmdictSnapshotPatch(t *testing.T) {
+	base := Jmdict{Entries: []JmdictEntry{testEntry(1, "私", "わたし", testSense("pn"))}}
+	target := Jmdict{Entries: []JmdictEntry{
+		testEntry(1, "私", "わたし", testSense("pn")),
+		testEntry(2, "学校", "がっこう", testSense("n")),
+	}}
+
+	var snapshot, patched bytes.Buffer
+	if err := WriteJmdictSnapshot(&snapshot, base); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyJmdictSnapshotPatch(&snapshot, &patched, NewJmdictPatch(base, target)); err != nil {
+		t.Fatal(err)
+	}
+
+	dict, err := ReadJmdictSnapshot(&patched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := JmdictRevision(dict), JmdictRevision(target); got != want {
+		t.Errorf("revision = %s, want %s", got, want)
+	}
+
+	// A failed patch writes nothing.
+	snapshot.Reset()
+	patched.Reset()
+	if err := WriteJmdictSnapshot(&snapshot, target); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyJmdictSnapshotPatch(&snapshot, &patched, NewJmdictPatch(base, target)); !errors.Is(err, ErrRevisionMismatch) || patched.Len() != 0 {
+		t.Errorf("got error %v and %d bytes written, want %v and nothing", err, patched.Len(), ErrRevisionMismatch)
+	}
+}
+
+func TestKanjidicPatchInsertOrder(t *testing.T) {
+	// KANJIDIC2 lists the characters in JIS order rather than by literal,
+	// so inserted characters go at the end.
+	base := Kanjidic{Characters: []KanjidicCharacter{testCharacter("学", "ガク", "study"), testCharacter("亜", "ア", "Asia")}}
+	target := Kanjidic{Characters: append(append([]KanjidicCharacter(nil), base.Characters...), testCharacter("一", "イチ", "one"))}
+
+	dic := Kanjidic{Characters: append([]KanjidicCharacter(nil), base.Characters...)}
+	if err := ApplyKanjidicPatch(&dic, NewKanjidicPatch(base, target)); err != nil {
+		t.Fatal(err)
+	}
+
+	var literals []string
+	for _, character := range dic.Characters {
+		literals = append(literals, character.Literal)
+	}
+	if want := []string{"学", "亜", "一"}; !reflect.DeepEqual(literals, want) {
+		t.Errorf("characters = %v, want %v", literals, want)
+	}
+}
@@ -0,0 +1,162 @@
+package jmdict
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SkipCode is a code of Halpern's System of Kanji Indexing by Patterns.
+// The pattern is 1 (left-right), 2 (up-down), 3 (enclosure) or 4 (solid).
+// For patterns 1-3, the parts are the stroke counts of the two components;
+// for pattern 4, the first part is the total stroke count and the second
+// identifies a horizontal line at the top (1) or bottom (2), a vertical
+// line through the character (3) or none of these (4).
+type SkipCode struct {
+	Pattern int
+	Part1   int
+	Part2   int
+}
+
+func ParseSkipCode(value string) (SkipCode, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 3 {
+		return SkipCode{}, fmt.Errorf("invalid SKIP code %q", value)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return SkipCode{}, fmt.Errorf("invalid SKIP code %q", value)
+		}
+		numbers[i] = n
+	}
+
+	code := SkipCode{Pattern: numbers[0], Part1: numbers[1], Part2: numbers[2]}
+	if code.Pattern > 4 || (code.Pattern == 4 && code.Part2 > 4) {
+		return SkipCode{}, fmt.Errorf("invalid SKIP code %q", value)
+	}
+
+	return code, nil
+}
+
+func (c SkipCode) String() string {
+	return fmt.Sprintf("%d-%d-%d", c.Pattern, c.Part1, c.Part2)
+}
+
+// FourCornerCode is a code of the Four Corner system, giving the shape of
+// the stroke at each corner of the character (top left, top right, bottom
+// left, bottom right) and optionally an extra "fifth corner" above the
+// bottom right.
+type FourCornerCode struct {
+	Corners  [4]int
+	Fifth    int
+	HasFifth bool
+}
+
+// ParseFourCornerCode parses codes of the form "4022.7", where the part
+// after the dot is the fifth corner.
+func ParseFourCornerCode(value string) (FourCornerCode, error) {
+	var code FourCornerCode
+
+	corners, fifth, hasFifth := strings.Cut(value, ".")
+	if len(corners) != len(code.Corners) || (hasFifth && len(fifth) != 1) {
+		return code, fmt.Errorf("invalid four corner code %q", value)
+	}
+
+	for i, c := range corners {
+		if c < '0' || c > '9' {
+			return code, fmt.Errorf("invalid four corner code %q", value)
+		}
+		code.Corners[i] = int(c - '0')
+	}
+
+	if hasFifth {
+		if fifth[0] < '0' || fifth[0] > '9' {
+			return code, fmt.Errorf("invalid four corner code %q", value)
+		}
+		code.Fifth = int(fifth[0] - '0')
+		code.HasFifth = true
+	}
+
+	return code, nil
+}
+
+func (c FourCornerCode) String() string {
+	text := fmt.Sprintf("%d%d%d%d", c.Corners[0], c.Corners[1], c.Corners[2], c.Corners[3])
+	if c.HasFifth {
+		text += fmt.Sprintf(".%d", c.Fifth)
+	}
+
+	return text
+}
+
+// DeRooCode is a code of the system of Father Joseph De Roo, as used in
+// his book "2001 Kanji". The last two digits identify the bottom element
+// of the character, and the preceding digits the top element.
+type DeRooCode struct {
+	Top    int
+	Bottom int
+}
+
+func ParseDeRooCode(value string) (DeRooCode, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || len(value) < 3 || len(value) > 4 || n < 0 {
+		return DeRooCode{}, fmt.Errorf("invalid De Roo code %q", value)
+	}
+
+	return DeRooCode{Top: n / 100, Bottom: n % 100}, nil
+}
+
+func (c DeRooCode) String() string {
+	return fmt.Sprintf("%d%02d", c.Top, c.Bottom)
+}
+
+// SpahnHadamitzkyCode is a descriptor of the Kanji & Kana dictionary by
+// Spahn and Hadamitzky, such as "2k1.4": the stroke count of the radical
+// (2), the letter identifying the radical (k), the number of remaining
+// strokes (1) and the position of the character in the resulting group
+// (4).
+type SpahnHadamitzkyCode struct {
+	RadicalStrokes int
+	Radical        rune
+	OtherStrokes   int
+	Index          int
+}
+
+func ParseSpahnHadamitzkyCode(value string) (SpahnHadamitzkyCode, error) {
+	var code SpahnHadamitzkyCode
+
+	fail := func() (SpahnHadamitzkyCode, error) {
+		return SpahnHadamitzkyCode{}, fmt.Errorf("invalid Spahn-Hadamitzky descriptor %q", value)
+	}
+
+	i := strings.IndexFunc(value, func(c rune) bool { return c >= 'a' && c <= 'z' })
+	if i < 1 {
+		return fail()
+	}
+
+	var err error
+	if code.RadicalStrokes, err = strconv.Atoi(value[:i]); err != nil {
+		return fail()
+	}
+	code.Radical = rune(value[i])
+
+	strokes, index, ok := strings.Cut(value[i+1:], ".")
+	if !ok {
+		return fail()
+	}
+	if code.OtherStrokes, err = strconv.Atoi(strokes); err != nil {
+		return fail()
+	}
+	if code.Index, err = strconv.Atoi(index); err != nil {
+		return fail()
+	}
+
+	return code, nil
+}
+
+func (c SpahnHadamitzkyCode) String() string {
+	return fmt.Sprintf("%d%c%d.%d", c.RadicalStrokes, c.Radical, c.OtherStrokes, c.Index)
+}
@@ -0,0 +1,81 @@
+package jmdict
+
+import "testing"
+
+func TestParseQueryCodes(t *testing.T) {
+	if code, err := ParseSkipCode("1-4-4"); err != nil || code != (SkipCode{Pattern: 1, Part1: 4, Part2: 4}) {
+		t.Errorf("ParseSkipCode = %+v, %v", code, err)
+	}
+
+	if code, err := ParseFourCornerCode("8073.2"); err != nil || code.String() != "8073.2" || code.Corners != [4]int{8, 0, 7, 3} {
+		t.Errorf("ParseFourCornerCode = %+v, %v", code, err)
+	}
+
+	if code, err := ParseDeRooCode("1750"); err != nil || code != (DeRooCode{Top: 17, Bottom: 50}) {
+		t.Errorf("ParseDeRooCode = %+v, %v", code, err)
+	}
+
+	if code, err := ParseSpahnHadamitzkyCode("2b7.2"); err != nil || code != (SpahnHadamitzkyCode{RadicalStrokes: 2, Radical: 'b', OtherStrokes: 7, Index: 2}) {
+		t.Errorf("ParseSpahnHadamitzkyCode = %+v, %v", code, err)
+	}
+}
+
+func TestParseQueryCodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) error
+		value string
+	}{
+		{"skip parts", func(v string) error { _, err := ParseSkipCode(v); return err }, "1-4"},
+		{"skip pattern", func(v string) error { _, err := ParseSkipCode(v); return err }, "5-1-1"},
+		{"skip solid", func(v string) error { _, err := ParseSkipCode(v); return err }, "4-3-5"},
+		{"four corner length", func(v string) error { _, err := ParseFourCornerCode(v); return err }, "807.2"},
+		{"four corner fifth", func(v string) error { _, err := ParseFourCornerCode(v); return err }, "8073.x"},
+		{"de roo", func(v string) error { _, err := ParseDeRooCode(v); return err }, "12"},
+		{"spahn hadamitzky", func(v string) error { _, err := ParseSpahnHadamitzkyCode(v); return err }, "2b7"},
+	}
+
+	for _, test := range tests {
+		if err := test.parse(test.value); err == nil {
+			t.Errorf("%s: %q parsed", test.name, test.value)
+		}
+	}
+}
+
+func TestKanjiIndexQueryCodes(t *testing.T) {
+	idx := NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{
+		{Literal: "食", QueryCode: []KanjidicQueryCode{
+			{Value: "2-2-7", Type: "skip"},
+			{Value: "8073.2", Type: "four_corner"},
+		}},
+		{Literal: "飯", QueryCode: []KanjidicQueryCode{
+			{Value: "1-9-4", Type: "skip"},
+			{Value: "2-2-7", Type: "skip", Misclassification: "posn"},
+			{Value: "8174.7", Type: "four_corner"},
+		}},
+		{Literal: "壊", QueryCode: []KanjidicQueryCode{{Value: "x", Type: "skip"}}},
+	}})
+
+	literals := func(characters []*KanjidicCharacter) string {
+		var text string
+		for _, character := range characters {
+			text += character.Literal
+		}
+		return text
+	}
+
+	code := SkipCode{Pattern: 2, Part1: 2, Part2: 7}
+	if got := literals(idx.LookupSkip(code, false)); got != "食" {
+		t.Errorf("LookupSkip = %q, want 食", got)
+	}
+	if got := literals(idx.LookupSkip(code, true)); got != "食飯" {
+		t.Errorf("LookupSkip with misclassifications = %q, want 食飯", got)
+	}
+
+	if got := literals(idx.LookupFourCorner(FourCornerCode{Corners: [4]int{8, 0, 7, 3}})); got != "食" {
+		t.Errorf("LookupFourCorner without fifth corner = %q, want 食", got)
+	}
+	if got := literals(idx.LookupFourCorner(FourCornerCode{Corners: [4]int{8, 0, 7, 3}, Fifth: 1, HasFifth: true})); got != "" {
+		t.Errorf("LookupFourCorner with other fifth corner = %q, want none", got)
+	}
+}
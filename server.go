@@ -0,0 +1,261 @@
+package jmdict
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Handler serves lookups in the dictionary indexes as JSON:
+//
+//	GET /words?term=...        JMdict entries by kanji or reading element
+//	GET /words?expression=...  JMdict entries by kanji element
+//	GET /words?reading=...     JMdict entries by reading element
+//	GET /words?gloss=...       JMdict entries by gloss words
+//	GET /words/{sequence}      a JMdict entry by sequence number
+//	GET /names?term=...        JMnedict entries (also expression, reading)
+//	GET /names/{sequence}      a JMnedict entry by sequence number
+//	GET /search?term=...       JMdict words followed by JMnedict names
+//	GET /kanji?literal=...     KANJIDIC2 characters for each of the literals
+//	GET /kanji/{literal}       a KANJIDIC2 character
+//
+// List endpoints accept a limit parameter. Errors are returned with the
+// appropriate status code and a JSON object with an "error" field. The
+// endpoints of dictionaries whose index is nil respond with 404. Like the
+// indexes, the handler is safe for concurrent use.
+type Handler struct {
+	words *JmdictIndex
+	names *JmnedictIndex
+	kanji *KanjiIndex
+	mux   *http.ServeMux
+}
+
+func NewHandler(words *JmdictIndex, names *JmnedictIndex, kanji *KanjiIndex) *Handler {
+	h := &Handler{
+		words: words,
+		names: names,
+		kanji: kanji,
+		mux:   http.NewServeMux(),
+	}
+
+	if words != nil {
+		h.mux.HandleFunc("/words", h.serveWords)
+		h.mux.HandleFunc("/words/", h.serveWord)
+	}
+
+	if names != nil {
+		h.mux.HandleFunc("/names", h.serveNames)
+		h.mux.HandleFunc("/names/", h.serveName)
+	}
+
+	if words != nil || names != nil {
+		h.mux.HandleFunc("/search", h.serveSearch)
+	}
+
+	if kanji != nil {
+		h.mux.HandleFunc("/kanji", h.serveKanjiList)
+		h.mux.HandleFunc("/kanji/", h.serveKanji)
+	}
+
+	h.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeHTTPError(w, http.StatusNotFound, "not found")
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeHTTPError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) serveWords(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	entries := []*JmdictEntry{}
+	switch {
+	case query.Has("term"):
+		entries = append(entries, h.words.LookupTerm(query.Get("term"))...)
+	case query.Has("expression"):
+		entries = append(entries, h.words.LookupExpression(query.Get("expression"))...)
+	case query.Has("reading"):
+		entries = append(entries, h.words.LookupReading(query.Get("reading"))...)
+	case query.Has("gloss"):
+		entries = append(entries, h.words.LookupGloss(query.Get("gloss"))...)
+	default:
+		writeHTTPError(w, http.StatusBadRequest, "missing term, expression, reading or gloss parameter")
+		return
+	}
+
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	writeHTTPJSON(w, entries)
+}
+
+func (h *Handler) serveWord(w http.ResponseWriter, r *http.Request) {
+	sequence, ok := parseSequence(w, r, "/words/")
+	if !ok {
+		return
+	}
+
+	entry, ok := h.words.LookupSequence(sequence)
+	if !ok {
+		writeHTTPError(w, http.StatusNotFound, "no entry with this sequence number")
+		return
+	}
+
+	writeHTTPJSON(w, entry)
+}
+
+func (h *Handler) serveNames(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	entries := []*JmnedictEntry{}
+	switch {
+	case query.Has("term"):
+		entries = append(entries, h.names.LookupTerm(query.Get("term"))...)
+	case query.Has("expression"):
+		entries = append(entries, h.names.LookupExpression(query.Get("expression"))...)
+	case query.Has("reading"):
+		entries = append(entries, h.names.LookupReading(query.Get("reading"))...)
+	default:
+		writeHTTPError(w, http.StatusBadRequest, "missing term, expression or reading parameter")
+		return
+	}
+
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	writeHTTPJSON(w, entries)
+}
+
+func (h *Handler) serveName(w http.ResponseWriter, r *http.Request) {
+	sequence, ok := parseSequence(w, r, "/names/")
+	if !ok {
+		return
+	}
+
+	entry, ok := h.names.LookupSequence(sequence)
+	if !ok {
+		writeHTTPError(w, http.StatusNotFound, "no entry with this sequence number")
+		return
+	}
+
+	writeHTTPJSON(w, entry)
+}
+
+func (h *Handler) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !query.Has("term") {
+		writeHTTPError(w, http.StatusBadRequest, "missing term parameter")
+		return
+	}
+
+	results := append([]SearchResult{}, SearchTerm(h.words, h.names, query.Get("term"))...)
+
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	writeHTTPJSON(w, results)
+}
+
+func (h *Handler) serveKanjiList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !query.Has("literal") {
+		writeHTTPError(w, http.StatusBadRequest, "missing literal parameter")
+		return
+	}
+
+	characters := []*KanjidicCharacter{}
+	for _, c := range query.Get("literal") {
+		if character, ok := h.kanji.Lookup(string(c)); ok {
+			characters = append(characters, character)
+		}
+	}
+
+	writeHTTPJSON(w, characters)
+}
+
+func (h *Handler) serveKanji(w http.ResponseWriter, r *http.Request) {
+	character, ok := h.kanji.Lookup(strings.TrimPrefix(r.URL.Path, "/kanji/"))
+	if !ok {
+		writeHTTPError(w, http.StatusNotFound, "no such character")
+		return
+	}
+
+	writeHTTPJSON(w, character)
+}
+
+func parseLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		writeHTTPError(w, http.StatusBadRequest, "invalid limit parameter")
+		return 0, false
+	}
+
+	return limit, true
+}
+
+func parseSequence(w http.ResponseWriter, r *http.Request, prefix string) (int, bool) {
+	sequence, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, prefix))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, "invalid sequence number")
+		return 0, false
+	}
+
+	return sequence, true
+}
+
+// writeHTTPJSON encodes the value before writing anything, so that an
+// encoding error can still be reported with a 500 status.
+func writeHTTPJSON(w http.ResponseWriter, value interface{}) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(buffer.Bytes())
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(struct {
+		Error string `json:"error"`
+	}{message})
+}
@@ -0,0 +1,124 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testHandler() *Handler {
+	kanji := NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{{Literal: "学"}, {Literal: "校"}}})
+	return NewHandler(testJmdictIndex(), nil, kanji)
+}
+
+// serveTest performs a request against the handler and decodes the JSON
+// response into value.
+func serveTest(t *testing.T, h http.Handler, method, target string, value interface{}) int {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("%s %s: Content-Type = %q", method, target, contentType)
+	}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), value); err != nil {
+		t.Fatalf("%s %s: %v in %q", method, target, err, recorder.Body.String())
+	}
+
+	return recorder.Code
+}
+
+func TestHandlerWords(t *testing.T) {
+	h := testHandler()
+
+	var entries []JmdictEntry
+	if code := serveTest(t, h, http.MethodGet, "/words?reading=%E3%81%9F%E3%81%B9%E3%82%8B", &entries); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if len(entries) != 2 || entries[0].Sequence != 2 {
+		t.Errorf("got %+v, want entries 2 and 1", entries)
+	}
+
+	if serveTest(t, h, http.MethodGet, "/words?gloss=school&limit=1", &entries); len(entries) != 1 || entries[0].Sequence != 3 {
+		t.Errorf("got %+v, want entry 3 alone", entries)
+	}
+
+	var entry JmdictEntry
+	if code := serveTest(t, h, http.MethodGet, "/words/4", &entry); code != http.StatusOK || entry.Sequence != 4 {
+		t.Errorf("status %d, entry %+v", code, entry)
+	}
+}
+
+func TestHandlerKanji(t *testing.T) {
+	h := testHandler()
+
+	var characters []KanjidicCharacter
+	if serveTest(t, h, http.MethodGet, "/kanji?literal=%E5%AD%A6%E6%A0%A1%E9%95%B7", &characters); len(characters) != 2 {
+		t.Errorf("got %+v, want 学 and 校", characters)
+	}
+
+	var character KanjidicCharacter
+	if code := serveTest(t, h, http.MethodGet, "/kanji/%E5%AD%A6", &character); code != http.StatusOK || character.Literal != "学" {
+		t.Errorf("status %d, character %+v", code, character)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	h := testHandler()
+
+	tests := []struct {
+		method string
+		target string
+		code   int
+	}{
+		{http.MethodPost, "/words?term=x", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/unknown", http.StatusNotFound},
+		{http.MethodGet, "/names?term=x", http.StatusNotFound},
+		{http.MethodGet, "/words/999", http.StatusNotFound},
+		{http.MethodGet, "/kanji/%E9%95%B7", http.StatusNotFound},
+		{http.MethodGet, "/words", http.StatusBadRequest},
+		{http.MethodGet, "/words?term=x&limit=-1", http.StatusBadRequest},
+		{http.MethodGet, "/words?term=x&limit=many", http.StatusBadRequest},
+		{http.MethodGet, "/words/x", http.StatusBadRequest},
+		{http.MethodGet, "/search", http.StatusBadRequest},
+		{http.MethodGet, "/kanji", http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		var response struct{ Error string }
+		if code := serveTest(t, h, test.method, test.target, &response); code != test.code || response.Error == "" {
+			t.Errorf("%s %s: status %d, error %q, want status %d", test.method, test.target, code, response.Error, test.code)
+		}
+	}
+}
+
+func TestHandlerEmptyResults(t *testing.T) {
+	h := testHandler()
+
+	for _, target := range []string{"/words?term=x", "/words?gloss=x", "/search?term=x", "/kanji?literal=x"} {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if got := strings.TrimSpace(recorder.Body.String()); recorder.Code != http.StatusOK || got != "[]" {
+			t.Errorf("%s: status %d, body %q, want an empty array", target, recorder.Code, got)
+		}
+	}
+}
+
+func TestWriteHTTPJSONError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writeHTTPJSON(recorder, math.Inf(1))
+
+	var response struct{ Error string }
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%v in %q", err, recorder.Body.String())
+	}
+	if recorder.Code != http.StatusInternalServerError || response.Error == "" {
+		t.Errorf("status %d, error %q, want status 500", recorder.Code, response.Error)
+	}
+}
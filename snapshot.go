@@ -0,0 +1,140 @@
+package jmdict
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Snapshots store a loaded dictionary in a compact binary form (gob) which
+// is much faster to read back than the original XML. A snapshot starts with
+// SnapshotMagic followed by the kind of dictionary it contains.
+const SnapshotMagic = "jmdict-snapshot\n"
+
+var ErrNotSnapshot = errors.New("not a dictionary snapshot")
+
+const (
+	snapshotJmdict   = "jmdict"
+	snapshotJmnedict = "jmnedict"
+	snapshotKanjidic = "kanjidic"
+)
+
+// IsSnapshot reports whether the data starts with the snapshot signature.
+func IsSnapshot(prefix []byte) bool {
+	return bytes.HasPrefix(prefix, []byte(SnapshotMagic))
+}
+
+func WriteJmdictSnapshot(writer io.Writer, dict Jmdict) error {
+	return writeSnapshot(writer, snapshotJmdict, &dict)
+}
+
+func ReadJmdictSnapshot(reader io.Reader) (Jmdict, error) {
+	var dict Jmdict
+	err := readSnapshot(reader, snapshotJmdict, &dict)
+	return dict, err
+}
+
+func WriteJmnedictSnapshot(writer io.Writer, dic Jmnedict) error {
+	return writeSnapshot(writer, snapshotJmnedict, &dic)
+}
+
+func ReadJmnedictSnapshot(reader io.Reader) (Jmnedict, error) {
+	var dic Jmnedict
+	err := readSnapshot(reader, snapshotJmnedict, &dic)
+	return dic, err
+}
+
+func WriteKanjidicSnapshot(writer io.Writer, dic Kanjidic) error {
+	return writeSnapshot(writer, snapshotKanjidic, &dic)
+}
+
+func ReadKanjidicSnapshot(reader io.Reader) (Kanjidic, error) {
+	var dic Kanjidic
+	err := readSnapshot(reader, snapshotKanjidic, &dic)
+	return dic, err
+}
+
+func writeSnapshot(writer io.Writer, kind string, dict interface{}) error {
+	w := bufio.NewWriter(writer)
+	if _, err := w.WriteString(SnapshotMagic); err != nil {
+		return err
+	}
+
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(kind); err != nil {
+		return err
+	}
+
+	if err := encoder.Encode(dict); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func readSnapshot(reader io.Reader, kind string, dict interface{}) error {
+	r := bufio.NewReader(reader)
+
+	magic := make([]byte, len(SnapshotMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !IsSnapshot(magic) {
+		return ErrNotSnapshot
+	}
+
+	decoder := gob.NewDecoder(r)
+
+	var actual string
+	if err := decoder.Decode(&actual); err != nil {
+		return err
+	}
+
+	if actual != kind {
+		return fmt.Errorf("snapshot contains %s, not %s", actual, kind)
+	}
+
+	return decoder.Decode(dict)
+}
+
+// ApplyJmdictSnapshotPatch reads a JMdict snapshot, applies the patch to it
+// as ApplyJmdictPatch does and writes the patched snapshot. Nothing is
+// written if the patch cannot be applied.
+func ApplyJmdictSnapshotPatch(reader io.Reader, writer io.Writer, patch JmdictPatch) error {
+	dict, err := ReadJmdictSnapshot(reader)
+	if err != nil {
+		return err
+	}
+
+	if err := ApplyJmdictPatch(&dict, patch); err != nil {
+		return err
+	}
+
+	return WriteJmdictSnapshot(writer, dict)
+}
+
+func ApplyJmnedictSnapshotPatch(reader io.Reader, writer io.Writer, patch JmnedictPatch) error {
+	dic, err := ReadJmnedictSnapshot(reader)
+	if err != nil {
+		return err
+	}
+
+	if err := ApplyJmnedictPatch(&dic, patch); err != nil {
+		return err
+	}
+
+	return WriteJmnedictSnapshot(writer, dic)
+}
+
+func ApplyKanjidicSnapshotPatch(reader io.Reader, writer io.Writer, patch KanjidicPatch) error {
+	dic, err := ReadKanjidicSnapshot(reader)
+	if err != nil {
+		return err
+	}
+
+	if err := ApplyKanjidicPatch(&dic, patch); err != nil {
+		return err
+	}
+
+	return WriteKanjidicSnapshot(writer, dic)
+}
@@ -0,0 +1,54 @@
+package jmdict
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJmdictSnapshotRoundTrip(t *testing.T) {
+	dict, _, err := LoadJmdict(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJmdictSnapshot(&buf, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsSnapshot(buf.Bytes()) {
+		t.Error("IsSnapshot = false")
+	}
+
+	loaded, err := ReadJmdictSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Info.Created != dict.Info.Created || len(loaded.Entries) != len(dict.Entries) {
+		t.Fatalf("got %+v, want %+v", loaded, dict)
+	}
+
+	for i := range loaded.Entries {
+		if got, want := testEntryXML(t, &loaded.Entries[i]), testEntryXML(t, &dict.Entries[i]); got != want {
+			t.Errorf("entry %d:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	if _, err := ReadJmdictSnapshot(strings.NewReader(testJmdictXML)); !errors.Is(err, ErrNotSnapshot) {
+		t.Errorf("reading XML: error = %v, want ErrNotSnapshot", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteKanjidicSnapshot(&buf, Kanjidic{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadJmdictSnapshot(&buf); err == nil {
+		t.Error("reading a KANJIDIC snapshot as JMdict succeeded")
+	}
+}
@@ -0,0 +1,716 @@
+package jmdict
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type SQLTable struct {
+	// The name of the table, prefixed with the dictionary it was
+	// generated from (e.g. jmdict_glosses).
+	Name string
+
+	// The columns of the table, in the order values appear in each row.
+	Columns []SQLColumn
+
+	// The names of the columns which together uniquely identify a row.
+	PrimaryKey []string
+
+	// The table data. Each value is either nil (NULL), an int or a string.
+	Rows [][]interface{}
+}
+
+type SQLColumn struct {
+	Name string
+
+	// The portable SQL type of the column, either INTEGER or TEXT.
+	Type string
+
+	// Optional foreign key target in the form table(column).
+	References string
+}
+
+const (
+	sqlInteger = "INTEGER"
+	sqlText    = "TEXT"
+)
+
+func (t *SQLTable) append(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+func sqlNullable(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
+
+func sqlOptionalInt(value int, ok bool, err error) (interface{}, error) {
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func sqlBool(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+func JmdictTables(dict Jmdict) []SQLTable {
+	entries := SQLTable{
+		Name:       "jmdict_entries",
+		Columns:    []SQLColumn{{Name: "sequence", Type: sqlInteger}},
+		PrimaryKey: []string{"sequence"},
+	}
+
+	kanji := SQLTable{
+		Name: "jmdict_kanji",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "kanji_index", Type: sqlInteger},
+			{Name: "expression", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "kanji_index"},
+	}
+
+	readings := SQLTable{
+		Name: "jmdict_readings",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "reading_index", Type: sqlInteger},
+			{Name: "reading", Type: sqlText},
+			{Name: "no_kanji", Type: sqlInteger},
+		},
+		PrimaryKey: []string{"sequence", "reading_index"},
+	}
+
+	restrictions := SQLTable{
+		Name: "jmdict_restrictions",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "reading_index", Type: sqlInteger},
+			{Name: "expression", Type: sqlText},
+		},
+	}
+
+	senses := SQLTable{
+		Name: "jmdict_senses",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+		},
+		PrimaryKey: []string{"sequence", "sense_index"},
+	}
+
+	senseRestrictions := SQLTable{
+		Name: "jmdict_sense_restrictions",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "kind", Type: sqlText},
+			{Name: "value", Type: sqlText},
+		},
+	}
+
+	glosses := SQLTable{
+		Name: "jmdict_glosses",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "gloss_index", Type: sqlInteger},
+			{Name: "content", Type: sqlText},
+			{Name: "language", Type: sqlText},
+			{Name: "gender", Type: sqlText},
+			{Name: "type", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "sense_index", "gloss_index"},
+	}
+
+	sources := SQLTable{
+		Name: "jmdict_sources",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "source_index", Type: sqlInteger},
+			{Name: "content", Type: sqlText},
+			{Name: "language", Type: sqlText},
+			{Name: "type", Type: sqlText},
+			{Name: "wasei", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "sense_index", "source_index"},
+	}
+
+	xrefs := SQLTable{
+		Name: "jmdict_xrefs",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "kind", Type: sqlText},
+			{Name: "target", Type: sqlText},
+		},
+	}
+
+	examples := SQLTable{
+		Name: "jmdict_examples",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "example_index", Type: sqlInteger},
+			{Name: "source", Type: sqlText},
+			{Name: "source_type", Type: sqlText},
+			{Name: "text", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "sense_index", "example_index"},
+	}
+
+	sentences := SQLTable{
+		Name: "jmdict_example_sentences",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "sense_index", Type: sqlInteger},
+			{Name: "example_index", Type: sqlInteger},
+			{Name: "language", Type: sqlText},
+			{Name: "text", Type: sqlText},
+		},
+	}
+
+	// Coded information fields (ke_inf, re_pri, pos, misc, etc.) for all
+	// element types share a single table, keyed by the element they
+	// belong to and the name of the originating XML element.
+	tags := SQLTable{
+		Name: "jmdict_tags",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "element", Type: sqlText},
+			{Name: "element_index", Type: sqlInteger},
+			{Name: "kind", Type: sqlText},
+			{Name: "value", Type: sqlText},
+		},
+	}
+
+	appendTags := func(sequence int, element string, index int, kind string, values []string) {
+		for _, value := range values {
+			tags.append(sequence, element, index, kind, value)
+		}
+	}
+
+	for _, entry := range dict.Entries {
+		seq := entry.Sequence
+		entries.append(seq)
+
+		for i, k := range entry.Kanji {
+			kanji.append(seq, i, k.Expression)
+			appendTags(seq, "k_ele", i, "ke_inf", k.Information)
+			appendTags(seq, "k_ele", i, "ke_pri", k.Priorities)
+		}
+
+		for i, r := range entry.Readings {
+			readings.append(seq, i, r.Reading, sqlBool(r.NoKanji != nil))
+			for _, restriction := range r.Restrictions {
+				restrictions.append(seq, i, restriction)
+			}
+			appendTags(seq, "r_ele", i, "re_inf", r.Information)
+			appendTags(seq, "r_ele", i, "re_pri", r.Priorities)
+		}
+
+		for i, sense := range entry.Sense {
+			senses.append(seq, i)
+
+			for _, value := range sense.RestrictedKanji {
+				senseRestrictions.append(seq, i, "stagk", value)
+			}
+			for _, value := range sense.RestrictedReadings {
+				senseRestrictions.append(seq, i, "stagr", value)
+			}
+
+			for _, target := range sense.References {
+				xrefs.append(seq, i, "xref", target)
+			}
+			for _, target := range sense.Antonyms {
+				xrefs.append(seq, i, "ant", target)
+			}
+
+			appendTags(seq, "sense", i, "pos", sense.PartsOfSpeech)
+			appendTags(seq, "sense", i, "field", sense.Fields)
+			appendTags(seq, "sense", i, "misc", sense.Misc)
+			appendTags(seq, "sense", i, "dial", sense.Dialects)
+			appendTags(seq, "sense", i, "s_inf", sense.Information)
+
+			for j, source := range sense.SourceLanguages {
+				sources.append(seq, i, j, source.Content, sqlNullable(source.Language), sqlNullable(source.Type), source.Wasei)
+			}
+
+			for j, gloss := range sense.Glossary {
+				glosses.append(seq, i, j, gloss.Content, sqlNullable(gloss.Language), sqlNullable(gloss.Gender), sqlNullable(gloss.Type))
+			}
+
+			for j, example := range sense.Examples {
+				examples.append(seq, i, j, example.Srce.ID, example.Srce.SrcType, example.Text)
+				for _, sentence := range example.Sentences {
+					sentences.append(seq, i, j, sentence.Lang, sentence.Text)
+				}
+			}
+		}
+	}
+
+	return []SQLTable{
+		entries,
+		kanji,
+		readings,
+		restrictions,
+		senses,
+		senseRestrictions,
+		glosses,
+		sources,
+		xrefs,
+		examples,
+		sentences,
+		tags,
+	}
+}
+
+func JmnedictTables(dic Jmnedict) []SQLTable {
+	entries := SQLTable{
+		Name:       "jmnedict_entries",
+		Columns:    []SQLColumn{{Name: "sequence", Type: sqlInteger}},
+		PrimaryKey: []string{"sequence"},
+	}
+
+	kanji := SQLTable{
+		Name: "jmnedict_kanji",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "kanji_index", Type: sqlInteger},
+			{Name: "expression", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "kanji_index"},
+	}
+
+	readings := SQLTable{
+		Name: "jmnedict_readings",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "reading_index", Type: sqlInteger},
+			{Name: "reading", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "reading_index"},
+	}
+
+	restrictions := SQLTable{
+		Name: "jmnedict_restrictions",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "reading_index", Type: sqlInteger},
+			{Name: "expression", Type: sqlText},
+		},
+	}
+
+	translations := SQLTable{
+		Name: "jmnedict_translations",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "translation_index", Type: sqlInteger},
+			{Name: "language", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "translation_index"},
+	}
+
+	details := SQLTable{
+		Name: "jmnedict_translation_details",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "translation_index", Type: sqlInteger},
+			{Name: "detail_index", Type: sqlInteger},
+			{Name: "content", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence", "translation_index", "detail_index"},
+	}
+
+	xrefs := SQLTable{
+		Name: "jmnedict_xrefs",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "translation_index", Type: sqlInteger},
+			{Name: "target", Type: sqlText},
+		},
+	}
+
+	tags := SQLTable{
+		Name: "jmnedict_tags",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmnedict_entries(sequence)"},
+			{Name: "element", Type: sqlText},
+			{Name: "element_index", Type: sqlInteger},
+			{Name: "kind", Type: sqlText},
+			{Name: "value", Type: sqlText},
+		},
+	}
+
+	appendTags := func(sequence int, element string, index int, kind string, values []string) {
+		for _, value := range values {
+			tags.append(sequence, element, index, kind, value)
+		}
+	}
+
+	for _, entry := range dic.Entries {
+		seq := entry.Sequence
+		entries.append(seq)
+
+		for i, k := range entry.Kanji {
+			kanji.append(seq, i, k.Expression)
+			appendTags(seq, "k_ele", i, "ke_inf", k.Information)
+			appendTags(seq, "k_ele", i, "ke_pri", k.Priorities)
+		}
+
+		for i, r := range entry.Readings {
+			readings.append(seq, i, r.Reading)
+			for _, restriction := range r.Restrictions {
+				restrictions.append(seq, i, restriction)
+			}
+			appendTags(seq, "r_ele", i, "re_inf", r.Information)
+			appendTags(seq, "r_ele", i, "re_pri", r.Priorities)
+		}
+
+		for i, trans := range entry.Translations {
+			translations.append(seq, i, sqlNullable(trans.Language))
+			for j, detail := range trans.Translations {
+				details.append(seq, i, j, detail)
+			}
+			for _, target := range trans.References {
+				xrefs.append(seq, i, target)
+			}
+			appendTags(seq, "trans", i, "name_type", trans.NameTypes)
+		}
+	}
+
+	return []SQLTable{
+		entries,
+		kanji,
+		readings,
+		restrictions,
+		translations,
+		details,
+		xrefs,
+		tags,
+	}
+}
+
+func KanjidicTables(dic Kanjidic) ([]SQLTable, error) {
+	characters := SQLTable{
+		Name: "kanjidic_characters",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText},
+			{Name: "grade", Type: sqlInteger},
+			{Name: "stroke_count", Type: sqlInteger},
+			{Name: "frequency", Type: sqlInteger},
+			{Name: "jlpt", Type: sqlInteger},
+		},
+		PrimaryKey: []string{"literal"},
+	}
+
+	strokeCounts := SQLTable{
+		Name: "kanjidic_stroke_counts",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "count_index", Type: sqlInteger},
+			{Name: "stroke_count", Type: sqlInteger},
+		},
+		PrimaryKey: []string{"literal", "count_index"},
+	}
+
+	codepoints := SQLTable{
+		Name: "kanjidic_codepoints",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlText},
+		},
+	}
+
+	radicals := SQLTable{
+		Name: "kanjidic_radicals",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlInteger},
+		},
+	}
+
+	radicalNames := SQLTable{
+		Name: "kanjidic_radical_names",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "name", Type: sqlText},
+		},
+	}
+
+	variants := SQLTable{
+		Name: "kanjidic_variants",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlText},
+		},
+	}
+
+	dicNumbers := SQLTable{
+		Name: "kanjidic_dic_numbers",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlText},
+			{Name: "volume", Type: sqlText},
+			{Name: "page", Type: sqlText},
+		},
+	}
+
+	queryCodes := SQLTable{
+		Name: "kanjidic_query_codes",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlText},
+			{Name: "misclassification", Type: sqlText},
+		},
+	}
+
+	readings := SQLTable{
+		Name: "kanjidic_readings",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "reading_index", Type: sqlInteger},
+			{Name: "rmgroup", Type: sqlInteger},
+			{Name: "type", Type: sqlText},
+			{Name: "value", Type: sqlText},
+			{Name: "on_type", Type: sqlText},
+			{Name: "jouyou_status", Type: sqlText},
+		},
+		PrimaryKey: []string{"literal", "reading_index"},
+	}
+
+	meanings := SQLTable{
+		Name: "kanjidic_meanings",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "meaning_index", Type: sqlInteger},
+			{Name: "rmgroup", Type: sqlInteger},
+			{Name: "language", Type: sqlText},
+			{Name: "meaning", Type: sqlText},
+		},
+		PrimaryKey: []string{"literal", "meaning_index"},
+	}
+
+	nanori := SQLTable{
+		Name: "kanjidic_nanori",
+		Columns: []SQLColumn{
+			{Name: "literal", Type: sqlText, References: "kanjidic_characters(literal)"},
+			{Name: "reading", Type: sqlText},
+		},
+	}
+
+	for i := range dic.Characters {
+		character := &dic.Characters[i]
+		lit := character.Literal
+		misc := character.Misc
+
+		grade, err := sqlOptionalInt(character.Grade())
+		if err != nil {
+			return nil, err
+		}
+
+		frequency, err := sqlOptionalInt(character.Frequency())
+		if err != nil {
+			return nil, err
+		}
+
+		jlpt, err := sqlOptionalInt(character.JLPT())
+		if err != nil {
+			return nil, err
+		}
+
+		counts, err := character.strokeCounts()
+		if err != nil {
+			return nil, err
+		}
+
+		var strokeCount interface{}
+		for i, count := range counts {
+			if i == 0 {
+				strokeCount = count
+			}
+			strokeCounts.append(lit, i, count)
+		}
+
+		characters.append(lit, grade, strokeCount, frequency, jlpt)
+
+		for _, cp := range character.Codepoint {
+			codepoints.append(lit, cp.Type, cp.Value)
+		}
+
+		for _, radical := range character.Radical {
+			value, err := character.parseInt(radical.Type+" radical", radical.Value)
+			if err != nil {
+				return nil, err
+			}
+			radicals.append(lit, radical.Type, value)
+		}
+
+		for _, name := range misc.RadicalName {
+			radicalNames.append(lit, name)
+		}
+
+		for _, variant := range misc.Variants {
+			variants.append(lit, variant.Type, variant.Value)
+		}
+
+		for _, dr := range character.DictionaryNumbers {
+			dicNumbers.append(lit, dr.Type, dr.Value, dr.Volume, dr.Page)
+		}
+
+		for _, qc := range character.QueryCode {
+			queryCodes.append(lit, qc.Type, qc.Value, qc.Misclassification)
+		}
+
+		if rm := character.ReadingMeaning; rm != nil {
+			var readingIndex, meaningIndex int
+			for group, rmgroup := range rm.Groups {
+				for _, reading := range rmgroup.Readings {
+					readings.append(lit, readingIndex, group, reading.Type, reading.Value, sqlNullable(reading.OnType), sqlNullable(reading.JouyouStatus))
+					readingIndex++
+				}
+				for _, meaning := range rmgroup.Meanings {
+					meanings.append(lit, meaningIndex, group, sqlNullable(meaning.Language), meaning.Meaning)
+					meaningIndex++
+				}
+			}
+			for _, reading := range rm.Nanori {
+				nanori.append(lit, reading)
+			}
+		}
+	}
+
+	return []SQLTable{
+		characters,
+		strokeCounts,
+		codepoints,
+		radicals,
+		radicalNames,
+		variants,
+		dicNumbers,
+		queryCodes,
+		readings,
+		meanings,
+		nanori,
+	}, nil
+}
+
+// WriteSQLSchema writes the CREATE TABLE statements (DDL) for the given
+// tables. Tables are emitted in order, so referenced tables must precede
+// the tables that reference them.
+func WriteSQLSchema(writer io.Writer, tables []SQLTable) error {
+	for _, table := range tables {
+		var defs []string
+		for _, column := range table.Columns {
+			defs = append(defs, fmt.Sprintf("\t%s %s", column.Name, column.Type))
+		}
+
+		if len(table.PrimaryKey) > 0 {
+			defs = append(defs, fmt.Sprintf("\tPRIMARY KEY (%s)", strings.Join(table.PrimaryKey, ", ")))
+		}
+
+		for _, column := range table.Columns {
+			if column.References != "" {
+				defs = append(defs, fmt.Sprintf("\tFOREIGN KEY (%s) REFERENCES %s", column.Name, column.References))
+			}
+		}
+
+		if _, err := fmt.Fprintf(writer, "CREATE TABLE %s (\n%s\n);\n\n", table.Name, strings.Join(defs, ",\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteSQLInserts writes one INSERT statement per row of each table.
+func WriteSQLInserts(writer io.Writer, tables []SQLTable) error {
+	for _, table := range tables {
+		var names []string
+		for _, column := range table.Columns {
+			names = append(names, column.Name)
+		}
+
+		prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (", table.Name, strings.Join(names, ", "))
+		for _, row := range table.Rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = sqlLiteral(value)
+			}
+
+			if _, err := fmt.Fprintf(writer, "%s%s);\n", prefix, strings.Join(values, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes the rows of a single table as CSV, preceded by a header
+// line containing the column names. NULL values are written as empty fields.
+func WriteCSV(writer io.Writer, table SQLTable) error {
+	w := csv.NewWriter(writer)
+
+	var header []string
+	for _, column := range table.Columns {
+		header = append(header, column.Name)
+	}
+
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			switch v := value.(type) {
+			case nil:
+				record[i] = ""
+			case int:
+				record[i] = strconv.Itoa(v)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func sqlLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	default:
+		return sqlLiteral(fmt.Sprint(v))
+	}
+}
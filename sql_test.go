@@ -0,0 +1,119 @@
+package jmdict
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJmdictTables(t *testing.T) {
+	entry := testEntry(1000, "食べる,喰べる", "たべる", testSense("v1,vt", "to eat"), testSense("", "to live on"))
+	entry.Kanji[0].Priorities = []string{"ichi1"}
+	entry.Readings[0].Restrictions = []string{"食べる"}
+
+	tables := make(map[string]SQLTable)
+	for _, table := range JmdictTables(Jmdict{Entries: []JmdictEntry{entry}}) {
+		tables[table.Name] = table
+	}
+
+	tests := []struct {
+		table string
+		rows  [][]interface{}
+	}{
+		{"jmdict_entries", [][]interface{}{{1000}}},
+		{"jmdict_kanji", [][]interface{}{{1000, 0, "食べる"}, {1000, 1, "喰べる"}}},
+		{"jmdict_readings", [][]interface{}{{1000, 0, "たべる", 0}}},
+		{"jmdict_restrictions", [][]interface{}{{1000, 0, "食べる"}}},
+		{"jmdict_senses", [][]interface{}{{1000, 0}, {1000, 1}}},
+		{"jmdict_glosses", [][]interface{}{{1000, 0, 0, "to eat", nil, nil, nil}, {1000, 1, 0, "to live on", nil, nil, nil}}},
+		{"jmdict_tags", [][]interface{}{
+			{1000, "k_ele", 0, "ke_pri", "ichi1"},
+			{1000, "sense", 0, "pos", "v1"},
+			{1000, "sense", 0, "pos", "vt"},
+		}},
+	}
+
+	for _, test := range tests {
+		table, ok := tables[test.table]
+		if !ok {
+			t.Errorf("no table %s", test.table)
+			continue
+		}
+
+		if !reflect.DeepEqual(table.Rows, test.rows) {
+			t.Errorf("%s rows = %v, want %v", test.table, table.Rows, test.rows)
+		}
+		for _, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				t.Errorf("%s row %v has %d values for %d columns", test.table, row, len(row), len(table.Columns))
+			}
+		}
+	}
+}
+
+func TestWriteSQL(t *testing.T) {
+	table := SQLTable{
+		Name: "jmdict_glosses",
+		Columns: []SQLColumn{
+			{Name: "sequence", Type: sqlInteger, References: "jmdict_entries(sequence)"},
+			{Name: "content", Type: sqlText},
+			{Name: "language", Type: sqlText},
+		},
+		PrimaryKey: []string{"sequence"},
+		Rows: [][]interface{}{
+			{1, "it's", nil},
+			{2, "a, \"b\"", "ger"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+		want  string
+	}{
+		{
+			name:  "schema",
+			write: func(b *bytes.Buffer) error { return WriteSQLSchema(b, []SQLTable{table}) },
+			want: "CREATE TABLE jmdict_glosses (\n" +
+				"\tsequence INTEGER,\n" +
+				"\tcontent TEXT,\n" +
+				"\tlanguage TEXT,\n" +
+				"\tPRIMARY KEY (sequence),\n" +
+				"\tFOREIGN KEY (sequence) REFERENCES jmdict_entries(sequence)\n" +
+				");\n\n",
+		},
+		{
+			name:  "inserts",
+			write: func(b *bytes.Buffer) error { return WriteSQLInserts(b, []SQLTable{table}) },
+			want: "INSERT INTO jmdict_glosses (sequence, content, language) VALUES (1, 'it''s', NULL);\n" +
+				"INSERT INTO jmdict_glosses (sequence, content, language) VALUES (2, 'a, \"b\"', 'ger');\n",
+		},
+		{
+			name:  "csv",
+			write: func(b *bytes.Buffer) error { return WriteCSV(b, table) },
+			want:  "sequence,content,language\n1,it's,\n2,\"a, \"\"b\"\"\",ger\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := test.write(&b); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKanjidicTablesInvalidValue(t *testing.T) {
+	grade := "first"
+	dic := Kanjidic{Characters: []KanjidicCharacter{{Literal: "食", Misc: KanjidicMisc{Grade: &grade}}}}
+
+	if _, err := KanjidicTables(dic); err == nil || !strings.Contains(err.Error(), "first") {
+		t.Errorf("got error %v, want one naming the invalid grade", err)
+	}
+}
@@ -0,0 +1,191 @@
+package jmdict
+
+// JmdictStats summarizes the contents of a JMdict dictionary. Tag
+// histograms count senses, and the priority histogram counts kanji and
+// reading elements.
+type JmdictStats struct {
+	Created         string `json:"created,omitempty"`
+	Entries         int    `json:"entries"`
+	KanjiElements   int    `json:"kanjiElements"`
+	ReadingElements int    `json:"readingElements"`
+	Senses          int    `json:"senses"`
+	Glosses         int    `json:"glosses"`
+
+	// Senses with at least one gloss in the language, and the number of
+	// glosses in the language, by ISO 639-2 code.
+	SensesByLanguage  map[string]int `json:"sensesByLanguage"`
+	GlossesByLanguage map[string]int `json:"glossesByLanguage"`
+
+	PartsOfSpeech map[string]int `json:"partsOfSpeech"`
+	Misc          map[string]int `json:"misc"`
+	Fields        map[string]int `json:"fields"`
+	Dialects      map[string]int `json:"dialects"`
+	Priorities    map[string]int `json:"priorities"`
+}
+
+// JmnedictStats summarizes the contents of a JMnedict dictionary. Name
+// types count entries, rather than translations.
+type JmnedictStats struct {
+	Created         string `json:"created,omitempty"`
+	Entries         int    `json:"entries"`
+	KanjiElements   int    `json:"kanjiElements"`
+	ReadingElements int    `json:"readingElements"`
+	Translations    int    `json:"translations"`
+
+	// Translation details by ISO 639-2 code.
+	TranslationsByLanguage map[string]int `json:"translationsByLanguage"`
+
+	NameTypes map[string]int `json:"nameTypes"`
+}
+
+// KanjidicStats summarizes the contents of a KANJIDIC2 dictionary.
+// Characters without a grade, JLPT level or stroke count are counted
+// under 0.
+type KanjidicStats struct {
+	Created    string `json:"created,omitempty"`
+	Characters int    `json:"characters"`
+
+	Grades       map[int]int `json:"grades"`
+	JlptLevels   map[int]int `json:"jlptLevels"`
+	StrokeCounts map[int]int `json:"strokeCounts"`
+
+	// Meanings by ISO 639-1 code and readings by r_type.
+	MeaningsByLanguage map[string]int `json:"meaningsByLanguage"`
+	ReadingsByType     map[string]int `json:"readingsByType"`
+	Nanori             int            `json:"nanori"`
+}
+
+// CollectJmdictStats counts the entries, senses and glosses of the
+// dictionary and tallies its tags. Tags are counted as loaded, so the
+// dictionary should be loaded without transform to group them by entity
+// code.
+func CollectJmdictStats(dict Jmdict) JmdictStats {
+	stats := JmdictStats{
+		Created:           dict.Info.Created,
+		Entries:           len(dict.Entries),
+		SensesByLanguage:  make(map[string]int),
+		GlossesByLanguage: make(map[string]int),
+		PartsOfSpeech:     make(map[string]int),
+		Misc:              make(map[string]int),
+		Fields:            make(map[string]int),
+		Dialects:          make(map[string]int),
+		Priorities:        make(map[string]int),
+	}
+
+	implied := newDictDefaults(&dict.Info.DTD)
+
+	for i := range dict.Entries {
+		entry := &dict.Entries[i]
+
+		stats.KanjiElements += len(entry.Kanji)
+		for _, kanji := range entry.Kanji {
+			countTags(stats.Priorities, kanji.Priorities)
+		}
+
+		stats.ReadingElements += len(entry.Readings)
+		for _, reading := range entry.Readings {
+			countTags(stats.Priorities, reading.Priorities)
+		}
+
+		stats.Senses += len(entry.Sense)
+		for _, sense := range entry.Sense {
+			stats.Glosses += len(sense.Glossary)
+
+			languages := make(map[string]bool)
+			for _, gloss := range sense.Glossary {
+				language := languageOf(gloss.Language, implied.glossLanguage)
+				stats.GlossesByLanguage[language]++
+				languages[language] = true
+			}
+			for language := range languages {
+				stats.SensesByLanguage[language]++
+			}
+
+			countTags(stats.PartsOfSpeech, sense.PartsOfSpeech)
+			countTags(stats.Misc, sense.Misc)
+			countTags(stats.Fields, sense.Fields)
+			countTags(stats.Dialects, sense.Dialects)
+		}
+	}
+
+	return stats
+}
+
+func CollectJmnedictStats(dic Jmnedict) JmnedictStats {
+	stats := JmnedictStats{
+		Created:                dic.Info.Created,
+		Entries:                len(dic.Entries),
+		TranslationsByLanguage: make(map[string]int),
+		NameTypes:              make(map[string]int),
+	}
+
+	implied := newDictDefaults(&dic.Info.DTD)
+
+	for i := range dic.Entries {
+		entry := &dic.Entries[i]
+
+		stats.KanjiElements += len(entry.Kanji)
+		stats.ReadingElements += len(entry.Readings)
+		stats.Translations += len(entry.Translations)
+
+		nameTypes := make(map[string]bool)
+		for _, trans := range entry.Translations {
+			language := languageOf(trans.Language, implied.translationLanguage)
+			stats.TranslationsByLanguage[language] += len(trans.Translations)
+
+			for _, nameType := range trans.NameTypes {
+				nameTypes[nameType] = true
+			}
+		}
+		for nameType := range nameTypes {
+			stats.NameTypes[nameType]++
+		}
+	}
+
+	return stats
+}
+
+func CollectKanjidicStats(dic Kanjidic) KanjidicStats {
+	stats := KanjidicStats{
+		Created:            dic.Info.Created,
+		Characters:         len(dic.Characters),
+		Grades:             make(map[int]int),
+		JlptLevels:         make(map[int]int),
+		StrokeCounts:       make(map[int]int),
+		MeaningsByLanguage: make(map[string]int),
+		ReadingsByType:     make(map[string]int),
+	}
+
+	for i := range dic.Characters {
+		character := &dic.Characters[i]
+
+		grade, _, _ := character.Grade()
+		stats.Grades[grade]++
+
+		level, _, _ := character.JLPT()
+		stats.JlptLevels[level]++
+
+		strokes, _ := character.StrokeCount()
+		stats.StrokeCounts[strokes]++
+
+		if rm := character.ReadingMeaning; rm != nil {
+			for _, group := range rm.Groups {
+				for _, reading := range group.Readings {
+					stats.ReadingsByType[reading.Type]++
+				}
+				for _, meaning := range group.Meanings {
+					stats.MeaningsByLanguage[languageOf(meaning.Language, "en")]++
+				}
+			}
+			stats.Nanori += len(rm.Nanori)
+		}
+	}
+
+	return stats
+}
+
+func countTags(counts map[string]int, tags []string) {
+	for _, tag := range tags {
+		counts[tag]++
+	}
+}
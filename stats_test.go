@@ -0,0 +1,87 @@
+package jmdict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollectJmdictStats(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := JmdictStats{
+		Created:           "2022-07-14",
+		Entries:           2,
+		KanjiElements:     2,
+		ReadingElements:   2,
+		Senses:            2,
+		Glosses:           3,
+		SensesByLanguage:  map[string]int{"eng": 2, "ger": 1},
+		GlossesByLanguage: map[string]int{"eng": 2, "ger": 1},
+		PartsOfSpeech:     map[string]int{"v1": 1, "n": 1},
+		Misc:              map[string]int{},
+		Fields:            map[string]int{},
+		Dialects:          map[string]int{},
+		Priorities:        map[string]int{"ichi1": 2},
+	}
+
+	if got := CollectJmdictStats(dict); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectJmnedictStats(t *testing.T) {
+	german := "ger"
+	dic := Jmnedict{Entries: []JmnedictEntry{{
+		Sequence: 1,
+		Readings: []JmnedictReading{{Reading: "とうきょう"}},
+		Translations: []JmnedictTranslation{
+			{NameTypes: []string{"place"}, Translations: []string{"Tokyo", "Tokio (old spelling)"}},
+			{NameTypes: []string{"place"}, Translations: []string{"Tokio"}, Language: &german},
+		},
+	}}}
+
+	stats := CollectJmnedictStats(dic)
+	if stats.Translations != 2 || !reflect.DeepEqual(stats.TranslationsByLanguage, map[string]int{"eng": 2, "ger": 1}) {
+		t.Errorf("translations = %d %v", stats.Translations, stats.TranslationsByLanguage)
+	}
+
+	if !reflect.DeepEqual(stats.NameTypes, map[string]int{"place": 1}) {
+		t.Errorf("NameTypes = %v, want place counted once per entry", stats.NameTypes)
+	}
+}
+
+func TestCollectKanjidicStats(t *testing.T) {
+	grade, jlpt, french := "2", "4", "fr"
+	dic := Kanjidic{Characters: []KanjidicCharacter{
+		{
+			Literal: "食",
+			Misc:    KanjidicMisc{Grade: &grade, JlptLevel: &jlpt, StrokeCounts: []string{"9"}},
+			ReadingMeaning: &KanjidicReadingMeaning{
+				Groups: []KanjidicRMGroup{{
+					Readings: []KanjidicReading{{Value: "ショク", Type: "ja_on"}, {Value: "た.べる", Type: "ja_kun"}},
+					Meanings: []KanjidicMeaning{{Meaning: "eat"}, {Meaning: "manger", Language: &french}},
+				}},
+				Nanori: []string{"け"},
+			},
+		},
+		{Literal: "喰", Misc: KanjidicMisc{StrokeCounts: []string{"12"}}},
+	}}
+
+	stats := CollectKanjidicStats(dic)
+
+	if !reflect.DeepEqual(stats.Grades, map[int]int{0: 1, 2: 1}) || !reflect.DeepEqual(stats.JlptLevels, map[int]int{0: 1, 4: 1}) {
+		t.Errorf("grades %v, JLPT levels %v", stats.Grades, stats.JlptLevels)
+	}
+
+	if !reflect.DeepEqual(stats.StrokeCounts, map[int]int{9: 1, 12: 1}) {
+		t.Errorf("StrokeCounts = %v", stats.StrokeCounts)
+	}
+
+	if !reflect.DeepEqual(stats.MeaningsByLanguage, map[string]int{"en": 1, "fr": 1}) || stats.Nanori != 1 {
+		t.Errorf("meanings %v, nanori %d", stats.MeaningsByLanguage, stats.Nanori)
+	}
+}
@@ -0,0 +1,80 @@
+package jmdict
+
+import "sort"
+
+// SearchResultKind tells which dictionary a search result comes from.
+type SearchResultKind string
+
+const (
+	SearchResultWord SearchResultKind = "word"
+	SearchResultName SearchResultKind = "name"
+)
+
+// SearchResult is a JMdict word or a JMnedict name found by SearchTerm.
+// Exactly one of Word and Name is set, according to Kind.
+type SearchResult struct {
+	Kind SearchResultKind `json:"kind"`
+	Word *JmdictEntry     `json:"word,omitempty"`
+	Name *JmnedictEntry   `json:"name,omitempty"`
+
+	// The score of the priority codes of the kanji and reading elements of
+	// the entry, computed the same way for words and names.
+	Score int `json:"score"`
+}
+
+// SearchTerm looks up the term in the kanji and reading elements of both
+// dictionaries, either of which may be nil. Words come before names, and
+// within each kind the entries with the highest score come first.
+func SearchTerm(words *JmdictIndex, names *JmnedictIndex, term string) []SearchResult {
+	var results []SearchResult
+	if words != nil {
+		for _, entry := range words.LookupTerm(term) {
+			results = append(results, SearchResult{
+				Kind:  SearchResultWord,
+				Word:  entry,
+				Score: jmdictPriorityScore(entry),
+			})
+		}
+	}
+
+	if names != nil {
+		for _, entry := range names.LookupTerm(term) {
+			results = append(results, SearchResult{
+				Kind:  SearchResultName,
+				Name:  entry,
+				Score: jmnedictPriorityScore(entry),
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind == SearchResultWord
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// Sequence returns the sequence number of the entry of the result.
+func (result *SearchResult) Sequence() int {
+	if result.Word != nil {
+		return result.Word.Sequence
+	}
+
+	return result.Name.Sequence
+}
+
+func jmnedictPriorityScore(entry *JmnedictEntry) int {
+	var priorities []string
+	for _, kanji := range entry.Kanji {
+		priorities = append(priorities, kanji.Priorities...)
+	}
+
+	for _, reading := range entry.Readings {
+		priorities = append(priorities, reading.Priorities...)
+	}
+
+	return priorityScore(priorities)
+}
@@ -0,0 +1,63 @@
+package jmdict
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerm(t *testing.T) {
+	rare := testEntry(1, "川崎", "かわさき", testSense("n", "river cape"))
+	words := NewJmdictIndex(&Jmdict{Entries: []JmdictEntry{rare}})
+
+	city := testName(5001, "川崎", "かわさき", "place")
+	city.Kanji[0].Priorities = []string{"spec1"}
+	names := NewJmnedictIndex(&Jmnedict{Entries: []JmnedictEntry{
+		testName(5000, "川崎", "かわさき", "surname"),
+		city,
+	}})
+
+	type result struct {
+		kind     SearchResultKind
+		sequence int
+	}
+
+	collect := func(results []SearchResult) []result {
+		var got []result
+		for i := range results {
+			got = append(got, result{results[i].Kind, results[i].Sequence()})
+		}
+		return got
+	}
+
+	want := []result{{SearchResultWord, 1}, {SearchResultName, 5001}, {SearchResultName, 5000}}
+	if got := collect(SearchTerm(words, names, "かわさき")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := collect(SearchTerm(nil, names, "川崎")); len(got) != 2 || got[0].kind != SearchResultName {
+		t.Errorf("names only: got %v", got)
+	}
+
+	if got := SearchTerm(words, nil, "東京"); len(got) != 0 {
+		t.Errorf("got %v, want no results", got)
+	}
+}
+
+func TestHandlerSearch(t *testing.T) {
+	h := NewHandler(testJmdictIndex(), nil, nil)
+
+	var results []SearchResult
+	if code := serveTest(t, h, http.MethodGet, "/search?term=%E5%AD%A6%E6%A0%A1", &results); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+
+	if len(results) != 1 || results[0].Kind != SearchResultWord || results[0].Word.Sequence != 3 {
+		t.Errorf("got %+v", results)
+	}
+
+	var response struct{ Error string }
+	if code := serveTest(t, h, http.MethodGet, "/search", &response); code != http.StatusBadRequest {
+		t.Errorf("missing term: status %d", code)
+	}
+}
@@ -0,0 +1,111 @@
+package jmdict
+
+import "strings"
+
+// Token is a part of a text found by Tokenize. Runs of text containing no
+// known word are returned as tokens without term or sequences.
+type Token struct {
+	Surface string `json:"surface"`
+
+	// Byte offset of the token in the text.
+	Start int `json:"start"`
+
+	// The dictionary form of the word and the inflections undone to get it,
+	// as returned by Deinflect.
+	Term    string   `json:"term,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Sequence numbers of the entries the word may be, most common first.
+	Sequences []int `json:"sequences,omitempty"`
+}
+
+// maxTokenLength bounds the length in characters of the words looked up
+// by Tokenize, well above that of nearly all JMdict headwords.
+const maxTokenLength = 24
+
+// Tokenize splits the text into words by greedy longest match: at each
+// position, the longest run of text which is a kanji or reading element
+// of the dictionary, or an inflection of one, becomes the next token.
+func (idx *JmdictIndex) Tokenize(text string) []Token {
+	runes := []rune(text)
+
+	var (
+		tokens []Token
+		start  int
+	)
+
+	for i := 0; i < len(runes); {
+		token, n := idx.longestMatch(runes[i:])
+		if n == 0 {
+			c := string(runes[i])
+			if last := len(tokens) - 1; last >= 0 && len(tokens[last].Sequences) == 0 {
+				tokens[last].Surface += c
+			} else {
+				tokens = append(tokens, Token{Surface: c, Start: start})
+			}
+
+			start += len(c)
+			i++
+			continue
+		}
+
+		token.Start = start
+		tokens = append(tokens, token)
+
+		start += len(token.Surface)
+		i += n
+	}
+
+	return tokens
+}
+
+// longestMatch returns the token for the longest prefix of the text which
+// is a word, and the length of the prefix in characters, or zero if no
+// prefix is.
+func (idx *JmdictIndex) longestMatch(text []rune) (Token, int) {
+	n := len(text)
+	if n > maxTokenLength {
+		n = maxTokenLength
+	}
+
+	for ; n > 0; n-- {
+		surface := string(text[:n])
+		for _, d := range Deinflect(surface) {
+			entries := idx.lookupDeinflection(&d)
+			if len(entries) == 0 {
+				continue
+			}
+
+			token := Token{Surface: surface, Term: d.Term, Reasons: d.Reasons}
+			for _, entry := range entries {
+				token.Sequences = append(token.Sequences, entry.Sequence)
+			}
+
+			return token, n
+		}
+	}
+
+	return Token{}, 0
+}
+
+// lookupDeinflection returns the entries the dictionary form may be. A
+// form of する is also looked up without it, as nouns taking する (勉強)
+// are listed without it.
+func (idx *JmdictIndex) lookupDeinflection(d *Deinflection) []*JmdictEntry {
+	var entries []*JmdictEntry
+	for _, entry := range idx.LookupTerm(d.Term) {
+		if d.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if stem := strings.TrimSuffix(d.Term, "する"); d.classes&classSuru != 0 && stem != d.Term && stem != "" {
+		for _, entry := range idx.LookupTerm(stem) {
+			if takesSuru(entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,144 @@
+package jmdict
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDeinflect(t *testing.T) {
+	tests := []struct {
+		word    string
+		term    string
+		reasons []string
+		pos     string
+	}{
+		{"食べる", "食べる", nil, "v1"},
+		{"食べなかった", "食べる", []string{"negative", "past"}, "v1"},
+		{"食べられる", "食べる", []string{"passive"}, "v1"},
+		{"食べさせられた", "食べる", []string{"causative", "passive", "past"}, "v1"},
+		{"書いて", "書く", []string{"-te"}, "v5k"},
+		{"泳いだ", "泳ぐ", []string{"past"}, "v5g"},
+		{"行った", "行く", []string{"past"}, "v5k-s"},
+		{"読みます", "読む", []string{"polite"}, "v5m"},
+		{"読みませんでした", "読む", []string{"polite", "past negative"}, "v5m"},
+		{"飲んでしまった", "飲む", []string{"-shimau", "past"}, "v5m"},
+		{"来なかった", "来る", []string{"negative", "past"}, "vk"},
+		{"しない", "する", []string{"negative"}, "vs-i"},
+		{"高くない", "高い", []string{"negative"}, "adj-i"},
+		{"高かった", "高い", []string{"past"}, "adj-i"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.word, func(t *testing.T) {
+			entry := testEntry(1, "", "", testSense(test.pos))
+			if !deinflectsTo(test.word, test.term, test.reasons, &entry) {
+				t.Errorf("Deinflect(%s) has no %s %v matching %s", test.word, test.term, test.reasons, test.pos)
+			}
+		})
+	}
+}
+
+func TestDeinflectMatches(t *testing.T) {
+	tests := []struct {
+		word string
+		term string
+		pos  string
+		want bool
+	}{
+		{"食べた", "食べる", "v1", true},
+		{"食べた", "食べる", "n", false},
+		{"書かない", "書く", "v5k", true},
+		{"書かない", "書く", "v1", false},
+		{"高かった", "高い", "adj-i", true},
+		{"高かった", "高い", "adj-na", false},
+		{"した", "する", "vs-i", true},
+		{"した", "する", "vk", false},
+		{"来た", "来る", "vk", true},
+	}
+
+	for _, test := range tests {
+		entry := testEntry(1, "", "", testSense(test.pos))
+
+		var got bool
+		for _, d := range Deinflect(test.word) {
+			if d.Term == test.term && len(d.Reasons) > 0 && d.Matches(&entry) {
+				got = true
+			}
+		}
+
+		if got != test.want {
+			t.Errorf("%s deinflected to %s matching %s = %v, want %v", test.word, test.term, test.pos, got, test.want)
+		}
+	}
+}
+
+// deinflectsTo reports whether the word deinflects to the term, with the
+// reasons, as a word the entry matches.
+func deinflectsTo(word, term string, reasons []string, entry *JmdictEntry) bool {
+	for _, d := range Deinflect(word) {
+		if d.Term == term && reflect.DeepEqual(d.Reasons, reasons) && d.Matches(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestTokenize(t *testing.T) {
+	dict := Jmdict{Entries: []JmdictEntry{
+		testEntry(1, "私", "わたし", testSense("pn")),
+		testEntry(2, "", "は", testSense("prt")),
+		testEntry(3, "学校", "がっこう", testSense("n")),
+		testEntry(4, "", "に", testSense("prt")),
+		testEntry(5, "行く", "いく", testSense("v5k-s")),
+		testEntry(6, "勉強", "べんきょう", testSense("n,vs")),
+		testEntry(7, "学", "がく", testSense("n")),
+	}}
+	idx := NewJmdictIndex(&dict)
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"私は学校に行った", []string{"私 1", "は 2", "学校 3", "に 4", "行った 5 行く past"}},
+		{"勉強しました", []string{"勉強しました 6 勉強する polite past"}},
+		{"ＡＢ学校", []string{"ＡＢ", "学校 3"}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			var got []string
+			start := 0
+			for _, token := range idx.Tokenize(test.text) {
+				if token.Start != start {
+					t.Errorf("token %s starts at %d, want %d", token.Surface, token.Start, start)
+				}
+				start += len(token.Surface)
+
+				got = append(got, describeToken(&token))
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Tokenize(%s) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+// describeToken returns the surface of the token, followed by its
+// sequences, and its term and reasons if it was inflected.
+func describeToken(token *Token) string {
+	parts := []string{token.Surface}
+	for _, sequence := range token.Sequences {
+		parts = append(parts, strconv.Itoa(sequence))
+	}
+	if len(token.Reasons) > 0 {
+		parts = append(parts, token.Term)
+		parts = append(parts, token.Reasons...)
+	}
+
+	return strings.Join(parts, " ")
+}
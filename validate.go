@@ -0,0 +1,244 @@
+package jmdict
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationProblem describes a violation of the constraints of the
+// dictionary format found in an entry.
+type ValidationProblem struct {
+	Sequence int    `json:"sequence"`
+	Message  string `json:"message"`
+}
+
+func (p ValidationProblem) String() string {
+	return fmt.Sprintf("%d: %s", p.Sequence, p.Message)
+}
+
+// ValidateJmdict checks the referential constraints of the dictionary:
+//
+//   - sequence numbers are unique
+//   - every entry has a reading and a sense, and every sense a gloss
+//   - re_restr and stagk values name a kanji element of the entry, and
+//     stagr values a reading element
+//   - xref and ant values name an existing entry (and sense, if given)
+//   - reading elements are written in kana, full-width letters and digits
+//     and the punctuation found in readings only
+//   - coded information fields use the entities declared in the DTD, or
+//     their descriptions when the dictionary was loaded with transform
+//
+// The entity check is skipped when the dictionary has no DTD, as is the
+// case when it was decoded from JSON. The gloss check is skipped when the
+// dictionary was loaded with WithLanguages, where senses may legitimately
+// have no glosses in the languages kept.
+func ValidateJmdict(dict Jmdict) []ValidationProblem {
+	v := newValidator(&dict.Info)
+
+	terms := make(map[string][]int)
+	for i := range dict.Entries {
+		entry := &dict.Entries[i]
+		for _, kanji := range entry.Kanji {
+			terms[kanji.Expression] = appendUnique(terms[kanji.Expression], i)
+		}
+		for _, reading := range entry.Readings {
+			terms[reading.Reading] = appendUnique(terms[reading.Reading], i)
+		}
+	}
+
+	for i := range dict.Entries {
+		entry := &dict.Entries[i]
+		v.sequence(entry.Sequence)
+
+		kanji := make(map[string]bool)
+		for _, k := range entry.Kanji {
+			kanji[k.Expression] = true
+			v.coded(entry.Sequence, "ke_inf", k.Information)
+		}
+
+		readings := make(map[string]bool)
+		for _, r := range entry.Readings {
+			readings[r.Reading] = true
+			v.reading(entry.Sequence, r.Reading)
+			v.restrictions(entry.Sequence, "re_restr", r.Restrictions, kanji)
+			v.coded(entry.Sequence, "re_inf", r.Information)
+		}
+
+		if len(entry.Readings) == 0 {
+			v.report(entry.Sequence, "entry has no reading elements")
+		}
+		if len(entry.Sense) == 0 {
+			v.report(entry.Sequence, "entry has no senses")
+		}
+
+		for j := range entry.Sense {
+			sense := &entry.Sense[j]
+
+			if len(sense.Glossary) == 0 && dict.Info.Languages == nil {
+				v.report(entry.Sequence, fmt.Sprintf("sense %d has no glosses", j+1))
+			}
+
+			v.restrictions(entry.Sequence, "stagk", sense.RestrictedKanji, kanji)
+			v.restrictions(entry.Sequence, "stagr", sense.RestrictedReadings, readings)
+
+			for _, ref := range sense.References {
+				if !resolveReference(dict.Entries, terms, ref) {
+					v.report(entry.Sequence, fmt.Sprintf("xref %q matches no entry", ref))
+				}
+			}
+			for _, ref := range sense.Antonyms {
+				if !resolveReference(dict.Entries, terms, ref) {
+					v.report(entry.Sequence, fmt.Sprintf("ant %q matches no entry", ref))
+				}
+			}
+
+			v.coded(entry.Sequence, "pos", sense.PartsOfSpeech)
+			v.coded(entry.Sequence, "field", sense.Fields)
+			v.coded(entry.Sequence, "misc", sense.Misc)
+			v.coded(entry.Sequence, "dial", sense.Dialects)
+		}
+	}
+
+	return v.problems
+}
+
+// ValidateJmnedict applies the checks of ValidateJmdict which are relevant
+// to names. Cross references are not checked, as they usually refer to
+// JMdict entries.
+func ValidateJmnedict(dic Jmnedict) []ValidationProblem {
+	v := newValidator(&dic.Info)
+
+	for i := range dic.Entries {
+		entry := &dic.Entries[i]
+		v.sequence(entry.Sequence)
+
+		kanji := make(map[string]bool)
+		for _, k := range entry.Kanji {
+			kanji[k.Expression] = true
+			v.coded(entry.Sequence, "ke_inf", k.Information)
+		}
+
+		for _, r := range entry.Readings {
+			v.reading(entry.Sequence, r.Reading)
+			v.restrictions(entry.Sequence, "re_restr", r.Restrictions, kanji)
+			v.coded(entry.Sequence, "re_inf", r.Information)
+		}
+
+		if len(entry.Readings) == 0 {
+			v.report(entry.Sequence, "entry has no reading elements")
+		}
+		if len(entry.Translations) == 0 {
+			v.report(entry.Sequence, "entry has no translations")
+		}
+
+		for j, trans := range entry.Translations {
+			if len(trans.Translations) == 0 && len(trans.NameTypes) == 0 {
+				v.report(entry.Sequence, fmt.Sprintf("translation %d is empty", j+1))
+			}
+			v.coded(entry.Sequence, "name_type", trans.NameTypes)
+		}
+	}
+
+	return v.problems
+}
+
+type validator struct {
+	problems  []ValidationProblem
+	sequences map[int]bool
+	codes     map[string]bool
+}
+
+func newValidator(info *DictionaryInfo) *validator {
+	v := &validator{sequences: make(map[int]bool)}
+
+	if entities := info.DTD.EntityMap(); len(entities) > 0 {
+		v.codes = make(map[string]bool)
+		for name, value := range entities {
+			v.codes[name] = true
+			v.codes[value] = true
+		}
+	}
+
+	return v
+}
+
+func (v *validator) report(sequence int, message string) {
+	v.problems = append(v.problems, ValidationProblem{Sequence: sequence, Message: message})
+}
+
+func (v *validator) sequence(sequence int) {
+	if v.sequences[sequence] {
+		v.report(sequence, "duplicate ent_seq")
+	}
+	v.sequences[sequence] = true
+}
+
+func (v *validator) reading(sequence int, reading string) {
+	if !isReadingString(reading) {
+		v.report(sequence, fmt.Sprintf("reb %q contains characters not allowed in readings", reading))
+	}
+}
+
+func (v *validator) restrictions(sequence int, name string, values []string, targets map[string]bool) {
+	for _, value := range values {
+		if !targets[value] {
+			v.report(sequence, fmt.Sprintf("%s %q matches no element of the entry", name, value))
+		}
+	}
+}
+
+func (v *validator) coded(sequence int, name string, values []string) {
+	if v.codes == nil {
+		return
+	}
+
+	for _, value := range values {
+		if !v.codes[value] {
+			v.report(sequence, fmt.Sprintf("%s %q is not a declared entity", name, value))
+		}
+	}
+}
+
+// resolveReference reports whether a cross reference of the form
+// "keb・reb・sense", where the reading and sense number are optional (and
+// the reading may be the first part), names an entry of the dictionary.
+func resolveReference(entries []JmdictEntry, terms map[string][]int, ref string) bool {
+	// Some katakana headwords contain the middle dot themselves.
+	if len(terms[ref]) > 0 {
+		return true
+	}
+
+	parts := strings.Split(ref, "・")
+
+	sense := 0
+	if len(parts) > 1 {
+		if n, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			sense = n
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	for _, i := range terms[parts[0]] {
+		entry := &entries[i]
+		if len(parts) > 1 && !hasReading(entry, parts[1]) {
+			continue
+		}
+		if sense > len(entry.Sense) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+func hasReading(entry *JmdictEntry, reading string) bool {
+	for _, r := range entry.Readings {
+		if r.Reading == reading {
+			return true
+		}
+	}
+
+	return false
+}
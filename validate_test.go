@@ -0,0 +1,107 @@
+package jmdict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateJmdict(t *testing.T) {
+	eat := testEntry(1, "食べる", "たべる", testSense("v1", "to eat"))
+	eat.Sense[0].References = []string{"飲む・のむ・1", "喰う"}
+	eat.Sense[0].Antonyms = []string{"食べる・たべる・2"}
+
+	broken := testEntry(2, "学校", "がっこう,gakkou", testSense("n", "school"), testSense("n"))
+	broken.Readings[0].Restrictions = []string{"学园"}
+	broken.Sense[0].RestrictedReadings = []string{"がっこ"}
+
+	drink := testEntry(3, "飲む", "のむ", testSense("v5m", "to drink"))
+	duplicate := testEntry(3, "", "ア・ラ・カルト", testSense("n", "a la carte"))
+	empty := testEntry(4, "", "")
+
+	problems := ValidateJmdict(Jmdict{Entries: []JmdictEntry{eat, broken, drink, duplicate, empty}})
+
+	var got []string
+	for _, problem := range problems {
+		got = append(got, problem.String())
+	}
+
+	want := []string{
+		`1: xref "喰う" matches no entry`,
+		`1: ant "食べる・たべる・2" matches no entry`,
+		`2: re_restr "学园" matches no element of the entry`,
+		`2: reb "gakkou" contains characters not allowed in readings`,
+		`2: stagr "がっこ" matches no element of the entry`,
+		`2: sense 2 has no glosses`,
+		`3: duplicate ent_seq`,
+		`4: entry has no reading elements`,
+		`4: entry has no senses`,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateJmdictLanguageFiltered(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML), WithLanguages("ger", "eng"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"eng", "ger"}; !reflect.DeepEqual(dict.Info.Languages, want) {
+		t.Errorf("Info.Languages = %v, want %v", dict.Info.Languages, want)
+	}
+
+	dict.Entries[0].Sense = append(dict.Entries[0].Sense, testSense("v1"))
+	if problems := ValidateJmdict(dict); len(problems) != 0 {
+		t.Errorf("problems in a language-filtered dictionary: %v", problems)
+	}
+}
+
+func TestValidateJmdictEntities(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := ValidateJmdict(dict); len(problems) != 0 {
+		t.Errorf("problems in a valid dictionary: %v", problems)
+	}
+
+	dict.Entries[0].Sense[0].Misc = []string{"arch"}
+	problems := ValidateJmdict(dict)
+	if len(problems) != 1 || problems[0].Message != `misc "arch" is not a declared entity` {
+		t.Errorf("got %v, want an undeclared misc entity", problems)
+	}
+
+	transformed, _, err := LoadJmdict(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := ValidateJmdict(transformed); len(problems) != 0 {
+		t.Errorf("problems in a dictionary loaded with transform: %v", problems)
+	}
+}
+
+func TestValidateJmnedict(t *testing.T) {
+	dic := Jmnedict{Entries: []JmnedictEntry{
+		{Sequence: 1, Readings: []JmnedictReading{{Reading: "とうきょう"}}, Translations: []JmnedictTranslation{{Translations: []string{"Tokyo"}}}},
+		{Sequence: 2, Readings: []JmnedictReading{{Reading: "きょうと", Restrictions: []string{"京都"}}}, Translations: []JmnedictTranslation{{}}},
+	}}
+
+	var got []string
+	for _, problem := range ValidateJmnedict(dic) {
+		got = append(got, problem.String())
+	}
+
+	want := []string{
+		`2: re_restr "京都" matches no element of the entry`,
+		`2: translation 1 is empty`,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,157 @@
+package jmdict
+
+import (
+	"strconv"
+	"strings"
+)
+
+// variantCode identifies a character by one of the codes variants refer to
+// it by, keyed by var_type.
+type variantCode struct {
+	kind  string
+	value string
+}
+
+// variantSources maps the var_type values to the codepoint, dictionary
+// reference and query code types holding the same numbers.
+var variantSources = map[string]struct {
+	codepoint string
+	reference string
+	queryCode string
+}{
+	"jis208":   {codepoint: "jis208"},
+	"jis212":   {codepoint: "jis212"},
+	"jis213":   {codepoint: "jis213"},
+	"ucs":      {codepoint: "ucs"},
+	"deroo":    {queryCode: "deroo"},
+	"njecd":    {reference: "halpern_njecd"},
+	"s_h":      {queryCode: "sh_desc"},
+	"nelson_c": {reference: "nelson_c"},
+	"oneill":   {reference: "oneill_names"},
+}
+
+func (idx *KanjiIndex) indexVariants() {
+	add := func(kind, value string, i int) {
+		key := variantCode{kind: kind, value: strings.ToLower(value)}
+		idx.codes[key] = appendUnique(idx.codes[key], i)
+	}
+
+	for i := range idx.dic.Characters {
+		character := &idx.dic.Characters[i]
+		for kind, source := range variantSources {
+			for _, cp := range character.Codepoint {
+				if cp.Type == source.codepoint {
+					add(kind, cp.Value, i)
+				}
+			}
+			for _, dr := range character.DictionaryNumbers {
+				if dr.Type == source.reference {
+					add(kind, dr.Value, i)
+				}
+			}
+			for _, qc := range character.QueryCode {
+				if qc.Type == source.queryCode {
+					add(kind, qc.Value, i)
+				}
+			}
+		}
+	}
+
+	// Old and variant forms outside the jouyou kanji fold to the jouyou
+	// kanji they refer to (學 to 学), as JMdict headwords are written
+	// with the latter.
+	for i := range idx.dic.Characters {
+		character := &idx.dic.Characters[i]
+		if isJouyou(character) {
+			continue
+		}
+
+		c, ok := singleRune(character.Literal)
+		if !ok {
+			continue
+		}
+
+		for _, literal := range idx.Variants(character.Literal) {
+			if target, ok := idx.Lookup(literal); ok && isJouyou(target) {
+				idx.shinjitai[c], _ = singleRune(literal)
+				break
+			}
+		}
+	}
+}
+
+func isJouyou(character *KanjidicCharacter) bool {
+	grade, ok, err := character.Grade()
+	return ok && err == nil && grade >= 1 && grade <= 8
+}
+
+// ResolveVariant returns the literals of the characters the variant refers
+// to. Unicode variants resolve even if the character is not in the
+// dictionary.
+func (idx *KanjiIndex) ResolveVariant(variant KanjidicVariant) []string {
+	var literals []string
+	for _, i := range idx.codes[variantCode{kind: variant.Type, value: strings.ToLower(variant.Value)}] {
+		literals = append(literals, idx.dic.Characters[i].Literal)
+	}
+
+	if len(literals) == 0 && variant.Type == "ucs" {
+		if n, err := strconv.ParseUint(variant.Value, 16, 32); err == nil {
+			literals = append(literals, string(rune(n)))
+		}
+	}
+
+	return literals
+}
+
+// Variants returns the literals of all of the variants of the character.
+func (idx *KanjiIndex) Variants(literal string) []string {
+	character, ok := idx.Lookup(literal)
+	if !ok {
+		return nil
+	}
+
+	var literals []string
+	for _, variant := range character.Misc.Variants {
+		for _, l := range idx.ResolveVariant(variant) {
+			if l != literal && !containsString(literals, l) {
+				literals = append(literals, l)
+			}
+		}
+	}
+
+	return literals
+}
+
+// Normalize replaces the old (kyuujitai) and variant forms (itaiji) in the
+// text by their modern jouyou forms (shinjitai). A character is replaced
+// when it is not a jouyou kanji itself, and one of its variants is.
+func (idx *KanjiIndex) Normalize(text string) string {
+	return strings.Map(func(c rune) rune {
+		if shinjitai, ok := idx.shinjitai[c]; ok {
+			return shinjitai
+		}
+		return c
+	}, text)
+}
+
+// LookupTermNormalized returns the entries matching the term as written,
+// or with its old and variant forms of kanji replaced by their modern
+// forms using the kanji index, most common entries first.
+func (idx *JmdictIndex) LookupTermNormalized(term string, kanji *KanjiIndex) []*JmdictEntry {
+	indices := mergeIndices(idx.expressions[term], idx.readings[term])
+	if normalized := kanji.Normalize(term); normalized != term {
+		indices = mergeIndices(indices, idx.expressions[normalized])
+	}
+
+	return idx.entries(indices)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
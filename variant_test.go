@@ -0,0 +1,62 @@
+package jmdict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testVariantIndex() *KanjiIndex {
+	grade := "1"
+
+	learn := testCharacter("学", "ガク,まな.ぶ", "study")
+	learn.Misc.Grade = &grade
+	learn.Codepoint = []KanjidicCodepoint{{Value: "5b66", Type: "ucs"}, {Value: "1-19-56", Type: "jis208"}}
+	learn.Misc.Variants = []KanjidicVariant{{Value: "1-24-81", Type: "jis212"}}
+
+	oldLearn := testCharacter("學", "ガク,まな.ぶ", "study")
+	oldLearn.Codepoint = []KanjidicCodepoint{{Value: "5b78", Type: "ucs"}, {Value: "1-24-81", Type: "jis212"}}
+	oldLearn.Misc.Variants = []KanjidicVariant{{Value: "1-19-56", Type: "jis208"}, {Value: "6588", Type: "ucs"}}
+
+	return NewKanjiIndex(&Kanjidic{Characters: []KanjidicCharacter{learn, oldLearn}})
+}
+
+func TestKanjiIndexVariants(t *testing.T) {
+	idx := testVariantIndex()
+
+	if got := idx.ResolveVariant(KanjidicVariant{Value: "1-19-56", Type: "jis208"}); !reflect.DeepEqual(got, []string{"学"}) {
+		t.Errorf("ResolveVariant(jis208) = %v, want 学", got)
+	}
+
+	if got := idx.ResolveVariant(KanjidicVariant{Value: "9FB0", Type: "ucs"}); !reflect.DeepEqual(got, []string{"龰"}) {
+		t.Errorf("ResolveVariant(ucs) = %v, want the code point itself", got)
+	}
+
+	if got := idx.ResolveVariant(KanjidicVariant{Value: "1", Type: "nelson_c"}); got != nil {
+		t.Errorf("ResolveVariant(nelson_c) = %v, want none", got)
+	}
+
+	if got := idx.Variants("學"); !reflect.DeepEqual(got, []string{"学", "斈"}) {
+		t.Errorf("Variants(學) = %q", got)
+	}
+
+	if got := idx.Variants("学"); !reflect.DeepEqual(got, []string{"學"}) {
+		t.Errorf("Variants(学) = %q", got)
+	}
+}
+
+func TestKanjiIndexNormalize(t *testing.T) {
+	idx := testVariantIndex()
+
+	if got := idx.Normalize("學校"); got != "学校" {
+		t.Errorf("Normalize(學校) = %q, want 学校", got)
+	}
+
+	if got := idx.Normalize("学校"); got != "学校" {
+		t.Errorf("Normalize(学校) = %q, want it unchanged", got)
+	}
+
+	words := NewJmdictIndex(&Jmdict{Entries: []JmdictEntry{testEntry(1, "学校", "がっこう", testSense("n", "school"))}})
+	if entries := words.LookupTermNormalized("學校", idx); len(entries) != 1 || entries[0].Sequence != 1 {
+		t.Errorf("LookupTermNormalized(學校) = %v", entries)
+	}
+}
@@ -0,0 +1,374 @@
+package jmdict
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// WriteJmdictXML writes the dictionary in the JMdict XML format, including
+// the DTD of the file it was loaded from when available. Coded information
+// fields (pos, misc, etc.) are written as entity references when the
+// dictionary was loaded without transform, so that the output can itself be
+// loaded either way.
+func WriteJmdictXML(writer io.Writer, dict Jmdict) error {
+	x := newXMLWriter(writer, &dict.Info)
+	x.prologue("JMdict")
+
+	for i := range dict.Entries {
+		x.jmdictEntry(&dict.Entries[i])
+	}
+
+	x.raw("</JMdict>\n")
+	return x.flush()
+}
+
+// WriteJmnedictXML writes the dictionary in the JMnedict XML format,
+// including the DTD of the file it was loaded from when available. Name
+// types are written as entity references when the dictionary was loaded
+// without transform.
+func WriteJmnedictXML(writer io.Writer, dic Jmnedict) error {
+	x := newXMLWriter(writer, &dic.Info)
+	x.prologue("JMnedict")
+
+	for i := range dic.Entries {
+		x.jmnedictEntry(&dic.Entries[i])
+	}
+
+	x.raw("</JMnedict>\n")
+	return x.flush()
+}
+
+// WriteKanjidicXML writes the dictionary in the KANJIDIC2 XML format,
+// header included. KANJIDIC2 declares no entities, so all values are
+// written as text.
+func WriteKanjidicXML(writer io.Writer, dic Kanjidic) error {
+	x := newXMLWriter(writer, &dic.Info)
+	x.prologue("kanjidic2")
+
+	x.open("header", 0)
+	x.element("file_version", 1, dic.Header.FileVersion)
+	x.element("database_version", 1, dic.Header.DatabaseVersion)
+	x.element("date_of_creation", 1, dic.Header.DateOfCreation)
+	x.close("header", 0)
+
+	for i := range dic.Characters {
+		x.kanjidicCharacter(&dic.Characters[i])
+	}
+
+	x.raw("</kanjidic2>\n")
+	return x.flush()
+}
+
+func (x *xmlWriter) jmdictEntry(entry *JmdictEntry) {
+	x.open("entry", 0)
+	x.element("ent_seq", 1, strconv.Itoa(entry.Sequence))
+
+	for _, kanji := range entry.Kanji {
+		x.open("k_ele", 1)
+		x.element("keb", 2, kanji.Expression)
+		x.coded("ke_inf", 2, kanji.Information)
+		x.elements("ke_pri", 2, kanji.Priorities)
+		x.close("k_ele", 1)
+	}
+
+	for _, reading := range entry.Readings {
+		x.open("r_ele", 1)
+		x.element("reb", 2, reading.Reading)
+		if reading.NoKanji != nil {
+			x.element("re_nokanji", 2, *reading.NoKanji)
+		}
+		x.elements("re_restr", 2, reading.Restrictions)
+		x.coded("re_inf", 2, reading.Information)
+		x.elements("re_pri", 2, reading.Priorities)
+		x.close("r_ele", 1)
+	}
+
+	for _, sense := range entry.Sense {
+		x.open("sense", 1)
+		x.elements("stagk", 2, sense.RestrictedKanji)
+		x.elements("stagr", 2, sense.RestrictedReadings)
+		x.coded("pos", 2, sense.PartsOfSpeech)
+		x.elements("xref", 2, sense.References)
+		x.elements("ant", 2, sense.Antonyms)
+		x.coded("field", 2, sense.Fields)
+		x.coded("misc", 2, sense.Misc)
+		x.elements("s_inf", 2, sense.Information)
+
+		for _, source := range sense.SourceLanguages {
+			var attrs []xml.Attr
+			if source.Language != nil && !source.LanguageImplied {
+				attrs = append(attrs, xmlAttr("xml:lang", *source.Language))
+			}
+			if source.Type != nil && !source.TypeImplied {
+				attrs = append(attrs, xmlAttr("ls_type", *source.Type))
+			}
+			if source.Wasei != "" {
+				attrs = append(attrs, xmlAttr("ls_wasei", source.Wasei))
+			}
+			x.element("lsource", 2, source.Content, attrs...)
+		}
+
+		x.coded("dial", 2, sense.Dialects)
+
+		for _, gloss := range sense.Glossary {
+			var attrs []xml.Attr
+			if gloss.Language != nil && !gloss.LanguageImplied {
+				attrs = append(attrs, xmlAttr("xml:lang", *gloss.Language))
+			}
+			if gloss.Gender != nil {
+				attrs = append(attrs, xmlAttr("g_gend", *gloss.Gender))
+			}
+			if gloss.Type != nil {
+				attrs = append(attrs, xmlAttr("g_type", *gloss.Type))
+			}
+			x.element("gloss", 2, gloss.Content, attrs...)
+		}
+
+		for _, example := range sense.Examples {
+			x.open("example", 2)
+			x.element("ex_srce", 3, example.Srce.ID, xmlAttr("exsrc_type", example.Srce.SrcType))
+			x.element("ex_text", 3, example.Text)
+			for _, sentence := range example.Sentences {
+				x.element("ex_sent", 3, sentence.Text, xmlAttr("xml:lang", sentence.Lang))
+			}
+			x.close("example", 2)
+		}
+
+		x.close("sense", 1)
+	}
+
+	x.close("entry", 0)
+}
+
+func (x *xmlWriter) jmnedictEntry(entry *JmnedictEntry) {
+	x.open("entry", 0)
+	x.element("ent_seq", 1, strconv.Itoa(entry.Sequence))
+
+	for _, kanji := range entry.Kanji {
+		x.open("k_ele", 1)
+		x.element("keb", 2, kanji.Expression)
+		x.coded("ke_inf", 2, kanji.Information)
+		x.elements("ke_pri", 2, kanji.Priorities)
+		x.close("k_ele", 1)
+	}
+
+	for _, reading := range entry.Readings {
+		x.open("r_ele", 1)
+		x.element("reb", 2, reading.Reading)
+		x.elements("re_restr", 2, reading.Restrictions)
+		x.coded("re_inf", 2, reading.Information)
+		x.elements("re_pri", 2, reading.Priorities)
+		x.close("r_ele", 1)
+	}
+
+	for _, trans := range entry.Translations {
+		x.open("trans", 1)
+		x.coded("name_type", 2, trans.NameTypes)
+		x.elements("xref", 2, trans.References)
+
+		// The language is declared on trans_det in the JMnedict DTD.
+		var attrs []xml.Attr
+		if trans.Language != nil && !trans.LanguageImplied {
+			attrs = append(attrs, xmlAttr("xml:lang", *trans.Language))
+		}
+		for _, detail := range trans.Translations {
+			x.element("trans_det", 2, detail, attrs...)
+		}
+
+		x.close("trans", 1)
+	}
+
+	x.close("entry", 0)
+}
+
+func (x *xmlWriter) kanjidicCharacter(character *KanjidicCharacter) {
+	x.open("character", 0)
+	x.element("literal", 1, character.Literal)
+
+	x.open("codepoint", 1)
+	for _, cp := range character.Codepoint {
+		x.element("cp_value", 2, cp.Value, xmlAttr("cp_type", cp.Type))
+	}
+	x.close("codepoint", 1)
+
+	x.open("radical", 1)
+	for _, radical := range character.Radical {
+		x.element("rad_value", 2, radical.Value, xmlAttr("rad_type", radical.Type))
+	}
+	x.close("radical", 1)
+
+	misc := &character.Misc
+	x.open("misc", 1)
+	x.optional("grade", 2, misc.Grade)
+	x.elements("stroke_count", 2, misc.StrokeCounts)
+	for _, variant := range misc.Variants {
+		x.element("variant", 2, variant.Value, xmlAttr("var_type", variant.Type))
+	}
+	x.optional("freq", 2, misc.Frequency)
+	x.elements("rad_name", 2, misc.RadicalName)
+	x.optional("jlpt", 2, misc.JlptLevel)
+	x.close("misc", 1)
+
+	if len(character.DictionaryNumbers) > 0 {
+		x.open("dic_number", 1)
+		for _, dr := range character.DictionaryNumbers {
+			attrs := []xml.Attr{xmlAttr("dr_type", dr.Type)}
+			if dr.Volume != "" {
+				attrs = append(attrs, xmlAttr("m_vol", dr.Volume))
+			}
+			if dr.Page != "" {
+				attrs = append(attrs, xmlAttr("m_page", dr.Page))
+			}
+			x.element("dic_ref", 2, dr.Value, attrs...)
+		}
+		x.close("dic_number", 1)
+	}
+
+	if len(character.QueryCode) > 0 {
+		x.open("query_code", 1)
+		for _, qc := range character.QueryCode {
+			attrs := []xml.Attr{xmlAttr("qc_type", qc.Type)}
+			if qc.Misclassification != "" {
+				attrs = append(attrs, xmlAttr("skip_misclass", qc.Misclassification))
+			}
+			x.element("q_code", 2, qc.Value, attrs...)
+		}
+		x.close("query_code", 1)
+	}
+
+	if rm := character.ReadingMeaning; rm != nil {
+		x.open("reading_meaning", 1)
+		for _, group := range rm.Groups {
+			x.open("rmgroup", 2)
+			for _, reading := range group.Readings {
+				attrs := []xml.Attr{xmlAttr("r_type", reading.Type)}
+				if reading.OnType != nil {
+					attrs = append(attrs, xmlAttr("on_type", *reading.OnType))
+				}
+				if reading.JouyouStatus != nil {
+					attrs = append(attrs, xmlAttr("r_status", *reading.JouyouStatus))
+				}
+				x.element("reading", 3, reading.Value, attrs...)
+			}
+			for _, meaning := range group.Meanings {
+				var attrs []xml.Attr
+				if meaning.Language != nil {
+					attrs = append(attrs, xmlAttr("m_lang", *meaning.Language))
+				}
+				x.element("meaning", 3, meaning.Meaning, attrs...)
+			}
+			x.close("rmgroup", 2)
+		}
+		x.elements("nanori", 2, rm.Nanori)
+		x.close("reading_meaning", 1)
+	}
+
+	x.close("character", 0)
+}
+
+type xmlWriter struct {
+	w        *bufio.Writer
+	info     *DictionaryInfo
+	declared map[string]string
+	err      error
+}
+
+func newXMLWriter(writer io.Writer, info *DictionaryInfo) *xmlWriter {
+	return &xmlWriter{
+		w:        bufio.NewWriter(writer),
+		info:     info,
+		declared: info.DTD.EntityMap(),
+	}
+}
+
+func xmlAttr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+func (x *xmlWriter) raw(text string) {
+	if x.err == nil {
+		_, x.err = x.w.WriteString(text)
+	}
+}
+
+func (x *xmlWriter) escaped(text string) {
+	if x.err == nil {
+		x.err = xml.EscapeText(x.w, []byte(text))
+	}
+}
+
+func (x *xmlWriter) indent(depth int) {
+	for i := 0; i < depth; i++ {
+		x.raw("\t")
+	}
+}
+
+func (x *xmlWriter) prologue(root string) {
+	x.raw(xml.Header)
+	if source := x.info.DTD.Source; source != "" {
+		x.raw("<!DOCTYPE " + root + " [\n" + source + "\n]>\n")
+	}
+	if x.info.Created != "" && root != "kanjidic2" {
+		x.raw("<!-- " + root + " created: " + x.info.Created + " -->\n")
+	}
+	x.raw("<" + root + ">\n")
+}
+
+func (x *xmlWriter) open(name string, depth int) {
+	x.indent(depth)
+	x.raw("<" + name + ">\n")
+}
+
+func (x *xmlWriter) close(name string, depth int) {
+	x.indent(depth)
+	x.raw("</" + name + ">\n")
+}
+
+func (x *xmlWriter) element(name string, depth int, value string, attrs ...xml.Attr) {
+	x.indent(depth)
+	x.raw("<" + name)
+	for _, attr := range attrs {
+		x.raw(" " + attr.Name.Local + "=\"")
+		x.escaped(attr.Value)
+		x.raw("\"")
+	}
+	x.raw(">")
+	x.escaped(value)
+	x.raw("</" + name + ">\n")
+}
+
+func (x *xmlWriter) optional(name string, depth int, value *string) {
+	if value != nil {
+		x.element(name, depth, *value)
+	}
+}
+
+func (x *xmlWriter) elements(name string, depth int, values []string) {
+	for _, value := range values {
+		x.element(name, depth, value)
+	}
+}
+
+// coded writes elements whose content is an entity reference when the
+// value is the name of a declared entity, or plain text otherwise.
+func (x *xmlWriter) coded(name string, depth int, values []string) {
+	for _, value := range values {
+		if _, ok := x.declared[value]; !ok {
+			x.element(name, depth, value)
+			continue
+		}
+
+		x.indent(depth)
+		x.raw("<" + name + ">&" + value + ";</" + name + ">\n")
+	}
+}
+
+func (x *xmlWriter) flush() error {
+	if x.err != nil {
+		return x.err
+	}
+
+	return x.w.Flush()
+}
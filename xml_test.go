@@ -0,0 +1,140 @@
+package jmdict
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteJmdictXMLRoundTrip(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJmdictXML(&buf, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "<pos>&v1;</pos>") {
+		t.Errorf("coded field not written as an entity reference:\n%s", buf.String())
+	}
+
+	loaded, _, err := LoadJmdictNoTransform(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Info.Created != dict.Info.Created || loaded.Info.DTD.Source != dict.Info.DTD.Source {
+		t.Errorf("Info = %+v, want %+v", loaded.Info, dict.Info)
+	}
+
+	if len(loaded.Entries) != len(dict.Entries) {
+		t.Fatalf("got %d entries, want %d", len(loaded.Entries), len(dict.Entries))
+	}
+
+	for i := range loaded.Entries {
+		if got, want := testEntryXML(t, &loaded.Entries[i]), testEntryXML(t, &dict.Entries[i]); got != want {
+			t.Errorf("entry %d:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
+
+func TestWriteJmdictXMLAttributes(t *testing.T) {
+	gender, glossType := "masc", "lit"
+	entry := testEntry(1, "", "た", testSense("n", "field"))
+	entry.Sense[0].Glossary[0].Gender = &gender
+	entry.Sense[0].Glossary[0].Type = &glossType
+
+	var buf bytes.Buffer
+	if err := WriteJmdictXML(&buf, Jmdict{Entries: []JmdictEntry{entry}}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _, err := LoadJmdict(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loaded.Entries[0].Sense[0].Glossary[0]; got.Gender == nil || *got.Gender != gender || got.Type == nil || *got.Type != glossType {
+		t.Errorf("gloss = %+v, want gender %q and type %q", got, gender, glossType)
+	}
+}
+
+func TestWriteJmnedictXMLRoundTrip(t *testing.T) {
+	dic := Jmnedict{Entries: []JmnedictEntry{{
+		Sequence: 5000001,
+		Kanji:    []JmnedictKanji{{Expression: "東京"}},
+		Readings: []JmnedictReading{{Reading: "とうきょう"}},
+		Translations: []JmnedictTranslation{{
+			NameTypes:    []string{"place"},
+			Translations: []string{"Tokyo"},
+		}},
+	}}}
+
+	var buf bytes.Buffer
+	if err := WriteJmnedictXML(&buf, dic); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _, err := LoadJmnedict(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.Entries, dic.Entries) {
+		t.Errorf("got %+v, want %+v", loaded.Entries, dic.Entries)
+	}
+}
+
+func TestWriteKanjidicXMLRoundTrip(t *testing.T) {
+	grade, language, onType, status := "2", "fr", "kan", "jy"
+	dic := Kanjidic{
+		Header: KanjidicHeader{FileVersion: "4", DatabaseVersion: "2022-195", DateOfCreation: "2022-07-14"},
+		Characters: []KanjidicCharacter{{
+			Literal:   "食",
+			Codepoint: []KanjidicCodepoint{{Value: "4e95", Type: "ucs"}},
+			Radical:   []KanjidicRadical{{Value: "184", Type: "classical"}},
+			Misc: KanjidicMisc{
+				Grade:        &grade,
+				StrokeCounts: []string{"9"},
+				Variants:     []KanjidicVariant{{Value: "1-89-45", Type: "jis208"}},
+			},
+			ReadingMeaning: &KanjidicReadingMeaning{
+				Groups: []KanjidicRMGroup{
+					{
+						Readings: []KanjidicReading{
+							{Value: "ショク", Type: "ja_on", OnType: &onType, JouyouStatus: &status},
+							{Value: "た.べる", Type: "ja_kun"},
+						},
+						Meanings: []KanjidicMeaning{{Meaning: "eat"}, {Meaning: "manger", Language: &language}},
+					},
+					{
+						Readings: []KanjidicReading{{Value: "ジキ", Type: "ja_on"}},
+						Meanings: []KanjidicMeaning{{Meaning: "food"}},
+					},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteKanjidicXML(&buf, dic); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadKanjidic(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Header != dic.Header {
+		t.Errorf("Header = %+v, want %+v", loaded.Header, dic.Header)
+	}
+
+	if !reflect.DeepEqual(loaded.Characters, dic.Characters) {
+		t.Errorf("got %+v, want %+v", loaded.Characters, dic.Characters)
+	}
+}
@@ -0,0 +1,490 @@
+package jmdict
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Yomichan dictionaries are zip archives holding index.json, which
+// describes the dictionary, and banks of terms, kanji and tags in JSON of
+// at most yomichanBankSize rows each. The archives are written in version 3
+// of the format.
+const (
+	yomichanFormat   = 3
+	yomichanBankSize = 10000
+)
+
+type yomichanIndex struct {
+	Title     string `json:"title"`
+	Format    int    `json:"format"`
+	Revision  string `json:"revision"`
+	Sequenced bool   `json:"sequenced"`
+}
+
+type yomichanTag struct {
+	category string
+	order    int
+	notes    string
+	score    int
+}
+
+// yomichanHeadword is a kanji element with one of its readings, or a
+// reading on its own.
+type yomichanHeadword struct {
+	kanji       string
+	reading     string
+	priorities  []string
+	information []string
+}
+
+// WriteJmdictYomichan writes the dictionary as a Yomichan dictionary
+// archive with the given title. Each sense of an entry is a term for every
+// kanji and reading it applies to; the entity codes of the senses become
+// tags, described by the DTD of the file the dictionary was loaded from.
+func WriteJmdictYomichan(writer io.Writer, dict Jmdict, title string) error {
+	y := newYomichanWriter(writer, &dict.Info)
+
+	var rows []interface{}
+	for i := range dict.Entries {
+		rows = y.jmdictTerms(rows, &dict.Entries[i])
+	}
+
+	if err := y.banks("term", rows); err != nil {
+		return err
+	}
+
+	return y.close(title, &dict.Info)
+}
+
+// WriteJmnedictYomichan writes the dictionary as a Yomichan dictionary
+// archive, with the name types of the translations as tags.
+func WriteJmnedictYomichan(writer io.Writer, dic Jmnedict, title string) error {
+	y := newYomichanWriter(writer, &dic.Info)
+
+	var rows []interface{}
+	for i := range dic.Entries {
+		rows = y.jmnedictTerms(rows, &dic.Entries[i])
+	}
+
+	if err := y.banks("term", rows); err != nil {
+		return err
+	}
+
+	return y.close(title, &dic.Info)
+}
+
+// WriteKanjidicYomichan writes the dictionary as a Yomichan kanji
+// dictionary archive. The meanings of all languages loaded are included;
+// use WithLanguages to keep a single one.
+func WriteKanjidicYomichan(writer io.Writer, dic Kanjidic, title string) error {
+	y := newYomichanWriter(writer, &dic.Info)
+
+	var rows []interface{}
+	for i := range dic.Characters {
+		rows = append(rows, y.kanjidicCharacter(&dic.Characters[i]))
+	}
+
+	if err := y.banks("kanji", rows); err != nil {
+		return err
+	}
+
+	return y.close(title, &dic.Info)
+}
+
+type yomichanWriter struct {
+	zip *zip.Writer
+
+	// The descriptions of the entity codes, and the codes of the
+	// descriptions for dictionaries loaded with transform.
+	descriptions map[string]string
+	codes        map[string]string
+
+	tags     map[string]yomichanTag
+	tagNames []string
+}
+
+func newYomichanWriter(writer io.Writer, info *DictionaryInfo) *yomichanWriter {
+	y := &yomichanWriter{
+		zip:          zip.NewWriter(writer),
+		descriptions: info.DTD.EntityMap(),
+		codes:        make(map[string]string),
+		tags:         make(map[string]yomichanTag),
+	}
+
+	for code, description := range y.descriptions {
+		y.codes[description] = code
+	}
+
+	return y
+}
+
+func (y *yomichanWriter) jmdictTerms(rows []interface{}, entry *JmdictEntry) []interface{} {
+	var headwords []yomichanHeadword
+	for _, kanji := range entry.Kanji {
+		for j := range entry.Readings {
+			reading := &entry.Readings[j]
+			if reading.NoKanji != nil || len(reading.Restrictions) > 0 && !stringSet(reading.Restrictions)[kanji.Expression] {
+				continue
+			}
+
+			headwords = append(headwords, yomichanHeadword{
+				kanji:       kanji.Expression,
+				reading:     reading.Reading,
+				priorities:  concatStrings(kanji.Priorities, reading.Priorities),
+				information: concatStrings(kanji.Information, reading.Information),
+			})
+		}
+	}
+
+	for _, reading := range entry.Readings {
+		if len(entry.Kanji) == 0 || reading.NoKanji != nil {
+			headwords = append(headwords, yomichanHeadword{
+				reading:     reading.Reading,
+				priorities:  reading.Priorities,
+				information: reading.Information,
+			})
+		}
+	}
+
+	for _, h := range headwords {
+		termTags := y.headwordTags(&h)
+
+		// Senses without parts of speech have those of the sense before.
+		var partsOfSpeech []string
+		for _, sense := range entry.Sense {
+			if len(sense.PartsOfSpeech) > 0 {
+				partsOfSpeech = sense.PartsOfSpeech
+			}
+			if !senseApplies(&sense, h.kanji, h.reading) {
+				continue
+			}
+
+			var definitionTags []string
+			for _, pos := range partsOfSpeech {
+				definitionTags = append(definitionTags, y.tag(pos, "partOfSpeech"))
+			}
+			for _, values := range [][]string{sense.Misc, sense.Fields, sense.Dialects} {
+				for _, value := range values {
+					definitionTags = append(definitionTags, y.tag(value, ""))
+				}
+			}
+
+			var glossary []string
+			for _, gloss := range sense.Glossary {
+				glossary = append(glossary, gloss.Content)
+			}
+			if len(glossary) == 0 {
+				continue
+			}
+
+			expression, reading := h.expression()
+			rows = append(rows, []interface{}{
+				expression,
+				reading,
+				strings.Join(definitionTags, " "),
+				yomichanRules(partsOfSpeech),
+				priorityScore(h.priorities),
+				glossary,
+				entry.Sequence,
+				termTags,
+			})
+		}
+	}
+
+	return rows
+}
+
+func (y *yomichanWriter) jmnedictTerms(rows []interface{}, entry *JmnedictEntry) []interface{} {
+	var headwords []yomichanHeadword
+	for _, kanji := range entry.Kanji {
+		for _, reading := range entry.Readings {
+			if len(reading.Restrictions) > 0 && !stringSet(reading.Restrictions)[kanji.Expression] {
+				continue
+			}
+
+			headwords = append(headwords, yomichanHeadword{
+				kanji:       kanji.Expression,
+				reading:     reading.Reading,
+				priorities:  concatStrings(kanji.Priorities, reading.Priorities),
+				information: concatStrings(kanji.Information, reading.Information),
+			})
+		}
+	}
+
+	if len(entry.Kanji) == 0 {
+		for _, reading := range entry.Readings {
+			headwords = append(headwords, yomichanHeadword{
+				reading:     reading.Reading,
+				priorities:  reading.Priorities,
+				information: reading.Information,
+			})
+		}
+	}
+
+	for _, h := range headwords {
+		termTags := y.headwordTags(&h)
+		for _, trans := range entry.Translations {
+			if len(trans.Translations) == 0 {
+				continue
+			}
+
+			var definitionTags []string
+			for _, nameType := range trans.NameTypes {
+				definitionTags = append(definitionTags, y.tag(nameType, "name"))
+			}
+
+			expression, reading := h.expression()
+			rows = append(rows, []interface{}{
+				expression,
+				reading,
+				strings.Join(definitionTags, " "),
+				"",
+				priorityScore(h.priorities),
+				trans.Translations,
+				entry.Sequence,
+				termTags,
+			})
+		}
+	}
+
+	return rows
+}
+
+func (y *yomichanWriter) kanjidicCharacter(character *KanjidicCharacter) []interface{} {
+	var onyomi, kunyomi, meanings []string
+	if rm := character.ReadingMeaning; rm != nil {
+		for _, group := range rm.Groups {
+			for _, reading := range group.Readings {
+				switch reading.Type {
+				case "ja_on":
+					onyomi = append(onyomi, reading.Value)
+				case "ja_kun":
+					kunyomi = append(kunyomi, reading.Value)
+				}
+			}
+			for _, meaning := range group.Meanings {
+				meanings = append(meanings, meaning.Meaning)
+			}
+		}
+	}
+
+	stats := make(map[string]string)
+	stat := func(name, category, value string) {
+		if _, ok := stats[name]; !ok {
+			stats[name] = value
+			y.define(name, yomichanTag{category: category})
+		}
+	}
+
+	misc := &character.Misc
+	if misc.Grade != nil {
+		stat("grade", "misc", *misc.Grade)
+	}
+	if len(misc.StrokeCounts) > 0 {
+		stat("strokes", "misc", misc.StrokeCounts[0])
+	}
+	if misc.Frequency != nil {
+		stat("freq", "misc", *misc.Frequency)
+	}
+	if misc.JlptLevel != nil {
+		stat("jlpt", "misc", *misc.JlptLevel)
+	}
+	for _, cp := range character.Codepoint {
+		stat(cp.Type, "code", cp.Value)
+	}
+	for _, qc := range character.QueryCode {
+		if qc.Misclassification == "" {
+			stat(qc.Type, "code", qc.Value)
+		}
+	}
+	for _, dr := range character.DictionaryNumbers {
+		stat(dr.Type, "index", dr.Value)
+	}
+
+	var tags []string
+	if grade, ok, err := character.Grade(); err == nil && ok {
+		switch {
+		case grade <= 8:
+			tags = append(tags, y.define("jouyou", yomichanTag{notes: "included in the list of jouyou kanji"}))
+		case grade <= 10:
+			tags = append(tags, y.define("jinmeiyou", yomichanTag{notes: "included in the list of jinmeiyou kanji"}))
+		}
+	}
+
+	return []interface{}{
+		character.Literal,
+		strings.Join(onyomi, " "),
+		strings.Join(kunyomi, " "),
+		strings.Join(tags, " "),
+		nonNilStrings(meanings),
+		stats,
+	}
+}
+
+// headwordTags returns the term tags of the headword: "P" for common
+// words, followed by the information codes of its kanji and reading.
+func (y *yomichanWriter) headwordTags(h *yomichanHeadword) string {
+	var tags []string
+	if containsAny(h.priorities, stringSet(commonPriorities)) {
+		tags = append(tags, y.define("P", yomichanTag{category: "popular", order: -10, notes: "common word", score: 10}))
+	}
+
+	for _, value := range h.information {
+		tags = append(tags, y.tag(value, ""))
+	}
+
+	return strings.Join(tags, " ")
+}
+
+// tag returns the name of the tag of an entity code or description, which
+// is the entity code, adding the tag to the tag bank. Yomichan separates
+// tags with spaces, so descriptions without a code have them replaced.
+func (y *yomichanWriter) tag(value, category string) string {
+	name, notes := value, y.descriptions[value]
+	if code, ok := y.codes[value]; ok {
+		name, notes = code, value
+	}
+
+	return y.define(strings.ReplaceAll(name, " ", "-"), yomichanTag{category: category, notes: notes})
+}
+
+func (y *yomichanWriter) define(name string, tag yomichanTag) string {
+	if _, ok := y.tags[name]; !ok {
+		y.tags[name] = tag
+		y.tagNames = append(y.tagNames, name)
+	}
+
+	return name
+}
+
+// banks writes the rows to numbered banks named after the prefix.
+func (y *yomichanWriter) banks(prefix string, rows []interface{}) error {
+	for i := 0; i < len(rows); i += yomichanBankSize {
+		end := i + yomichanBankSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		name := fmt.Sprintf("%s_bank_%d.json", prefix, i/yomichanBankSize+1)
+		if err := y.json(name, rows[i:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (y *yomichanWriter) json(name string, value interface{}) error {
+	w, err := y.zip.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(value)
+}
+
+// close writes the tag bank and the index, and finishes the archive. The
+// revision of the dictionary is its creation date.
+func (y *yomichanWriter) close(title string, info *DictionaryInfo) error {
+	sort.Strings(y.tagNames)
+
+	var rows []interface{}
+	for _, name := range y.tagNames {
+		tag := y.tags[name]
+		rows = append(rows, []interface{}{name, tag.category, tag.order, tag.notes, tag.score})
+	}
+
+	if err := y.banks("tag", rows); err != nil {
+		return err
+	}
+
+	revision := info.Created
+	if revision == "" {
+		revision = "unknown"
+	}
+
+	index := yomichanIndex{
+		Title:     title,
+		Format:    yomichanFormat,
+		Revision:  revision,
+		Sequenced: true,
+	}
+
+	if err := y.json("index.json", &index); err != nil {
+		return err
+	}
+
+	return y.zip.Close()
+}
+
+// expression returns the expression and reading of the term, leaving the
+// reading empty for words written in kana, as Yomichan expects.
+func (h *yomichanHeadword) expression() (string, string) {
+	if h.kanji == "" {
+		return h.reading, ""
+	}
+
+	return h.kanji, h.reading
+}
+
+// senseApplies reports whether the sense applies to the kanji (empty for
+// words written in kana) and reading, taking stagk and stagr into account.
+func senseApplies(sense *JmdictSense, kanji, reading string) bool {
+	if kanji != "" && len(sense.RestrictedKanji) > 0 && !stringSet(sense.RestrictedKanji)[kanji] {
+		return false
+	}
+
+	return len(sense.RestrictedReadings) == 0 || stringSet(sense.RestrictedReadings)[reading]
+}
+
+// yomichanRules returns the deinflection rules of the parts of speech,
+// which tell Yomichan how the term inflects. Parts of speech are matched
+// by their entity codes or, for dictionaries loaded with transform, by
+// their descriptions.
+func yomichanRules(partsOfSpeech []string) string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, pos := range partsOfSpeech {
+		var rule string
+		switch {
+		case pos == "v1" || pos == "v1-s" || strings.HasPrefix(pos, "Ichidan verb"):
+			rule = "v1"
+		case strings.HasPrefix(pos, "v5") || strings.HasPrefix(pos, "Godan verb"):
+			rule = "v5"
+		case pos == "vk" || strings.HasPrefix(pos, "Kuru verb"):
+			rule = "vk"
+		case pos == "vs" || pos == "vs-i" || pos == "vs-s" || strings.HasPrefix(pos, "suru verb") ||
+			pos == "noun or participle which takes the aux. verb suru":
+			rule = "vs"
+		case pos == "adj-i" || pos == "adj-ix" || strings.HasPrefix(pos, "adjective (keiyoushi)"):
+			rule = "adj-i"
+		}
+
+		if rule != "" && !seen[rule] {
+			seen[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	return strings.Join(rules, " ")
+}
+
+func concatStrings(a, b []string) []string {
+	return append(append([]string(nil), a...), b...)
+}
+
+// nonNilStrings returns the values, or an empty slice for nil, so that they
+// are encoded as an empty JSON array rather than null.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
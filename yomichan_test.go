@@ -0,0 +1,124 @@
+package jmdict
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readYomichanArchive returns the contents of the files of the archive.
+func readYomichanArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestWriteJmdictYomichan(t *testing.T) {
+	dict, _, err := LoadJmdictNoTransform(strings.NewReader(testJmdictXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJmdictYomichan(&buf, dict, "JMdict"); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readYomichanArchive(t, buf.Bytes())
+
+	var index yomichanIndex
+	if err := json.Unmarshal([]byte(files["index.json"]), &index); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (yomichanIndex{Title: "JMdict", Format: 3, Revision: "2022-07-14", Sequenced: true}); index != want {
+		t.Errorf("index = %+v, want %+v", index, want)
+	}
+
+	want := `[["食べる","たべる","v1","v1",100,["to eat","essen"],1358280,"P"],["学校","がっこう","n","",0,["school"],1206730,""]]`
+	if got := strings.TrimSpace(files["term_bank_1.json"]); got != want {
+		t.Errorf("term bank =\n%s\nwant\n%s", got, want)
+	}
+
+	if !strings.Contains(files["tag_bank_1.json"], `["v1","partOfSpeech",0,"Ichidan verb",0]`) {
+		t.Errorf("tag bank = %s", files["tag_bank_1.json"])
+	}
+}
+
+func TestWriteKanjidicYomichan(t *testing.T) {
+	grade := "2"
+	dic := Kanjidic{Characters: []KanjidicCharacter{{
+		Literal: "食",
+		Misc:    KanjidicMisc{Grade: &grade, StrokeCounts: []string{"9"}},
+		ReadingMeaning: &KanjidicReadingMeaning{Groups: []KanjidicRMGroup{{
+			Readings: []KanjidicReading{{Value: "ショク", Type: "ja_on"}, {Value: "た.べる", Type: "ja_kun"}},
+			Meanings: []KanjidicMeaning{{Meaning: "eat"}},
+		}}},
+	}}}
+
+	var buf bytes.Buffer
+	if err := WriteKanjidicYomichan(&buf, dic, "KANJIDIC2"); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows [][]interface{}
+	if err := json.Unmarshal([]byte(readYomichanArchive(t, buf.Bytes())["kanji_bank_1.json"]), &rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+
+	want := []interface{}{"食", "ショク", "た.べる", "jouyou", []interface{}{"eat"}, map[string]interface{}{"grade": "2", "strokes": "9"}}
+	if !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("got %v, want %v", rows[0], want)
+	}
+}
+
+func TestYomichanRules(t *testing.T) {
+	tests := []struct {
+		partsOfSpeech []string
+		want          string
+	}{
+		{[]string{"v1", "vt"}, "v1"},
+		{[]string{"v5k-s"}, "v5"},
+		{[]string{"n", "vs"}, "vs"},
+		{[]string{"adj-ix"}, "adj-i"},
+		{[]string{"vk"}, "vk"},
+		{[]string{"Godan verb with 'ku' ending", "Godan verb with 'ru' ending"}, "v5"},
+		{[]string{"noun or participle which takes the aux. verb suru"}, "vs"},
+		{[]string{"n", "adj-na"}, ""},
+	}
+
+	for _, test := range tests {
+		if got := yomichanRules(test.partsOfSpeech); got != test.want {
+			t.Errorf("yomichanRules(%v) = %q, want %q", test.partsOfSpeech, got, test.want)
+		}
+	}
+}